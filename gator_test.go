@@ -1,8 +1,22 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/l2thet/Gator/internal/database"
+	"github.com/l2thet/Gator/internal/rss"
 )
 
 func TestHandlerLogin(t *testing.T) {
@@ -28,3 +42,258 @@ func TestHandlerLogin(t *testing.T) {
 		}
 	}
 }
+
+func TestUnfetchableFeed(t *testing.T) {
+	cases := []struct {
+		input    error
+		expected bool
+	}{
+		{
+			input:    fmt.Errorf("error fetching feed: %w", &url.Error{Op: "Get", URL: "http://10.0.0.1/feed", Err: fmt.Errorf("%w: 10.0.0.1", rss.ErrForbiddenAddress)}),
+			expected: true,
+		},
+		{
+			input:    fmt.Errorf("error fetching feed: %w", fmt.Errorf("%w: %q", rss.ErrUnsupportedScheme, "file")),
+			expected: true,
+		},
+		{
+			input:    fmt.Errorf("%w and looking for feeds on it failed: no feeds", rss.ErrHTMLPage),
+			expected: true,
+		},
+		{
+			input:    errNoFeedChosen,
+			expected: true,
+		},
+		{
+			input:    fmt.Errorf("error fetching feed: %w", &rss.StatusError{StatusCode: 502, Status: "502 Bad Gateway"}),
+			expected: false,
+		},
+		{
+			input:    fmt.Errorf("error fetching feed: %w", &url.Error{Op: "Get", URL: "https://example.com/feed", Err: errors.New("connection refused")}),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		actual := unfetchableFeed(c.input)
+		if actual != c.expected {
+			t.Errorf("Expected %v but got %v for %v", c.expected, actual, c.input)
+		}
+	}
+}
+
+func TestNextFetchInterval(t *testing.T) {
+	minInterval, maxInterval := 10*time.Minute, 24*time.Hour
+
+	cases := []struct {
+		current  time.Duration
+		newPosts int
+		expected time.Duration
+	}{
+		{current: time.Hour, newPosts: 3, expected: 30 * time.Minute},
+		{current: time.Hour, newPosts: 0, expected: 90 * time.Minute},
+		{current: 15 * time.Minute, newPosts: 1, expected: minInterval},
+		{current: 20 * time.Hour, newPosts: 0, expected: maxInterval},
+		{current: maxInterval, newPosts: 0, expected: maxInterval},
+		{current: 0, newPosts: 0, expected: minInterval},
+	}
+
+	for _, c := range cases {
+		actual := nextFetchInterval(c.current, c.newPosts, minInterval, maxInterval)
+		if actual != c.expected {
+			t.Errorf("Expected %v but got %v for %v with %d new posts", c.expected, actual, c.current, c.newPosts)
+		}
+	}
+}
+
+func TestFailureBackoff(t *testing.T) {
+	minInterval, maxInterval := 10*time.Minute, 2*time.Hour
+
+	cases := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{failures: 0, expected: minInterval},
+		{failures: 1, expected: minInterval},
+		{failures: 2, expected: 20 * time.Minute},
+		{failures: 3, expected: 40 * time.Minute},
+		{failures: 4, expected: 80 * time.Minute},
+		{failures: 5, expected: maxInterval},
+		{failures: 100, expected: maxInterval},
+	}
+
+	for _, c := range cases {
+		actual := failureBackoff(c.failures, minInterval, maxInterval)
+		if actual != c.expected {
+			t.Errorf("Expected %v but got %v after %d failures", c.expected, actual, c.failures)
+		}
+	}
+}
+
+func TestSavePostAdoptsLegacyPost(t *testing.T) {
+	cases := []struct {
+		article  rss.Item
+		expected []string
+	}{
+		{
+			// The post is unchanged, so the upsert returns nothing, but the
+			// adoption must still be committed.
+			article:  rss.Item{ID: "https://example.com/?p=42", Link: "https://example.com/hello/", Title: "Hello"},
+			expected: []string{"AdoptLegacyPost", "UpsertPost"},
+		},
+		{
+			// Keyed on its link all along, so there is nothing to adopt.
+			article:  rss.Item{Link: "https://example.com/world/", Title: "World"},
+			expected: []string{"UpsertPost"},
+		},
+	}
+
+	for _, c := range cases {
+		fake := &fakeDB{}
+		db := sql.OpenDB(fake)
+		s := &State{db: database.New(db), dbConn: db}
+		feed := database.Feed{ID: uuid.New(), Url: "https://example.com/feed/"}
+
+		result, err := savePost(context.Background(), s, feed, c.article, time.Now())
+		if err != nil {
+			t.Errorf("Expected no error but got %v", err)
+		}
+		if result != postUnchanged {
+			t.Errorf("Expected %v but got %v", postUnchanged, result)
+		}
+		if !slices.Equal(fake.committed, c.expected) {
+			t.Errorf("Expected %v to be committed but got %v", c.expected, fake.committed)
+		}
+
+		db.Close()
+	}
+}
+
+// fakeDB is just enough of a database/sql driver to see which queries get
+// committed. Queries return no rows and statements always succeed.
+type fakeDB struct {
+	mu        sync.Mutex
+	inTx      bool
+	pending   []string
+	committed []string
+}
+
+func (d *fakeDB) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: d}, nil
+}
+
+func (d *fakeDB) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+// record notes a query by its sqlc name.
+func (d *fakeDB) record(query string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	name := query
+	if fields := strings.Fields(query); len(fields) > 2 && fields[1] == "name:" {
+		name = fields[2]
+	}
+
+	if d.inTx {
+		d.pending = append(d.pending, name)
+	} else {
+		d.committed = append(d.committed, name)
+	}
+}
+
+func (d *fakeDB) endTx(commit bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if commit {
+		d.committed = append(d.committed, d.pending...)
+	}
+	d.pending = nil
+	d.inTx = false
+	return nil
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("use sql.OpenDB")
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	c.db.inTx = true
+	return fakeTx{db: c.db}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.db.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.db.record(query)
+	return fakeRows{}, nil
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx fakeTx) Commit() error {
+	return tx.db.endTx(true)
+}
+
+func (tx fakeTx) Rollback() error {
+	return tx.db.endTx(false)
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string {
+	return nil
+}
+
+func (fakeRows) Close() error {
+	return nil
+}
+
+func (fakeRows) Next([]driver.Value) error {
+	return io.EOF
+}
+
+func TestPostponeUntil(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	maxInterval := 24 * time.Hour
+
+	cases := []struct {
+		until    time.Time
+		expected time.Time
+	}{
+		{until: now.Add(time.Minute), expected: now.Add(time.Minute)},
+		{until: now.Add(maxInterval), expected: now.Add(maxInterval)},
+		{until: now.Add(99999999 * time.Second), expected: now.Add(maxInterval)},
+	}
+
+	for _, c := range cases {
+		actual := postponeUntil(c.until, now, maxInterval)
+		if !actual.Equal(c.expected) {
+			t.Errorf("Expected %v but got %v", c.expected, actual)
+		}
+	}
+}
@@ -5,4 +5,11 @@ go 1.22.5
 require (
 	github.com/google/uuid v1.6.0
 	github.com/lib/pq v1.10.9
+	golang.org/x/net v0.28.0
+	golang.org/x/term v0.23.0
+)
+
+require (
+	golang.org/x/sys v0.23.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
 )
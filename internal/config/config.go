@@ -4,13 +4,60 @@ import (
 	"encoding/json"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 )
 
 const configFileName = ".gatorconfig.json"
 
+const (
+	defaultFetchConcurrency = 4
+	defaultMinFetchInterval = 5 * time.Minute
+	defaultMaxFetchInterval = 24 * time.Hour
+	defaultMaxFeedFailures  = 10
+	defaultPodcastDir       = "gator-podcasts"
+)
+
 type Config struct {
-	DbURL           string `json:"db_url"`
-	CurrentUserName string `json:"current_user_name"`
+	DbURL            string   `json:"db_url"`
+	CurrentUserName  string   `json:"current_user_name"`
+	FetchConcurrency int      `json:"fetch_concurrency,omitempty"`
+	MinFetchInterval Duration `json:"min_fetch_interval,omitempty"`
+	MaxFetchInterval Duration `json:"max_fetch_interval,omitempty"`
+	MaxFeedFailures  int      `json:"max_feed_failures,omitempty"`
+	ConnectTimeout   Duration `json:"connect_timeout,omitempty"`
+	FetchTimeout     Duration `json:"fetch_timeout,omitempty"`
+	MaxFeedSize      int64    `json:"max_feed_size,omitempty"`
+	HTTPProxy        string   `json:"http_proxy,omitempty"`
+	UserAgent        string   `json:"user_agent,omitempty"`
+	AllowedHosts     []string `json:"allowed_hosts,omitempty"`
+	HostMinDelay     Duration `json:"host_min_delay,omitempty"`
+	HostBurst        int      `json:"host_burst,omitempty"`
+	PodcastDir       string   `json:"podcast_dir,omitempty"`
+}
+
+// Duration is a time.Duration written as a string like "30m" in the config
+// file.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
 }
 
 func Read() (Config, error) {
@@ -47,6 +94,59 @@ func (cfg *Config) SetUser(name string) error {
 	return nil
 }
 
+// Concurrency returns how many feeds the aggregator fetches at once.
+func (cfg *Config) Concurrency() int {
+	if cfg.FetchConcurrency <= 0 {
+		return defaultFetchConcurrency
+	}
+
+	return cfg.FetchConcurrency
+}
+
+// FetchIntervalBounds returns the shortest and longest time the aggregator
+// waits between fetches of the same feed.
+func (cfg *Config) FetchIntervalBounds() (time.Duration, time.Duration) {
+	minInterval := time.Duration(cfg.MinFetchInterval)
+	if minInterval <= 0 {
+		minInterval = defaultMinFetchInterval
+	}
+
+	maxInterval := time.Duration(cfg.MaxFetchInterval)
+	if maxInterval <= 0 {
+		maxInterval = defaultMaxFetchInterval
+	}
+	if maxInterval < minInterval {
+		maxInterval = minInterval
+	}
+
+	return minInterval, maxInterval
+}
+
+// FeedFailureLimit returns how many fetches in a row a feed may fail before
+// the aggregator disables it.
+func (cfg *Config) FeedFailureLimit() int {
+	if cfg.MaxFeedFailures <= 0 {
+		return defaultMaxFeedFailures
+	}
+
+	return cfg.MaxFeedFailures
+}
+
+// PodcastDirectory returns where downloaded podcast episodes are saved,
+// which is a folder in the home directory unless podcast_dir is set.
+func (cfg *Config) PodcastDirectory() (string, error) {
+	if cfg.PodcastDir != "" {
+		return cfg.PodcastDir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, defaultPodcastDir), nil
+}
+
 func getConfigFilePath() (string, error) {
 	path, err := os.UserHomeDir()
 	if err != nil {
@@ -22,7 +22,7 @@ VALUES (
     $5,
     $6
 )
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, last_error, failure_count, last_success_at, etag, last_modified, fetch_interval_seconds, next_fetch_at, disabled, title, site_url, description, image_url, language, parse_mode, postpone_count
 `
 
 type CreateFeedParams struct {
@@ -52,6 +52,21 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.LastError,
+		&i.FailureCount,
+		&i.LastSuccessAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.FetchIntervalSeconds,
+		&i.NextFetchAt,
+		&i.Disabled,
+		&i.Title,
+		&i.SiteUrl,
+		&i.Description,
+		&i.ImageUrl,
+		&i.Language,
+		&i.ParseMode,
+		&i.PostponeCount,
 	)
 	return i, err
 }
@@ -15,21 +15,36 @@ import (
 
 const getFeeds = `-- name: GetFeeds :many
 SELECT
-    f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, f.last_fetched_at,
+    f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, f.last_fetched_at, f.last_error, f.failure_count, f.last_success_at, f.etag, f.last_modified, f.fetch_interval_seconds, f.next_fetch_at, f.disabled, f.title, f.site_url, f.description, f.image_url, f.language, f.parse_mode, f.postpone_count,
     u.name as user_name
 FROM feeds f
 JOIN users u ON f.user_id = u.id
 `
 
 type GetFeedsRow struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
-	UserName      string
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Name                 string
+	Url                  string
+	UserID               uuid.UUID
+	LastFetchedAt        sql.NullTime
+	LastError            sql.NullString
+	FailureCount         int32
+	LastSuccessAt        sql.NullTime
+	Etag                 sql.NullString
+	LastModified         sql.NullString
+	FetchIntervalSeconds int32
+	NextFetchAt          sql.NullTime
+	Disabled             bool
+	Title                sql.NullString
+	SiteUrl              sql.NullString
+	Description          sql.NullString
+	ImageUrl             sql.NullString
+	Language             sql.NullString
+	ParseMode            sql.NullString
+	PostponeCount        int32
+	UserName             string
 }
 
 func (q *Queries) GetFeeds(ctx context.Context) ([]GetFeedsRow, error) {
@@ -49,6 +64,21 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]GetFeedsRow, error) {
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.LastError,
+			&i.FailureCount,
+			&i.LastSuccessAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.FetchIntervalSeconds,
+			&i.NextFetchAt,
+			&i.Disabled,
+			&i.Title,
+			&i.SiteUrl,
+			&i.Description,
+			&i.ImageUrl,
+			&i.Language,
+			&i.ParseMode,
+			&i.PostponeCount,
 			&i.UserName,
 		); err != nil {
 			return nil, err
@@ -11,10 +11,11 @@ import (
 
 const getFeedstoFetch = `-- name: GetFeedstoFetch :many
 SELECT
-    id, created_at, updated_at, name, url, user_id, last_fetched_at
+    id, created_at, updated_at, name, url, user_id, last_fetched_at, last_error, failure_count, last_success_at, etag, last_modified, fetch_interval_seconds, next_fetch_at, disabled, title, site_url, description, image_url, language, parse_mode, postpone_count
 FROM feeds
-WHERE last_fetched_at IS NULL
-ORDER BY updated_at DESC
+WHERE NOT disabled
+    AND (next_fetch_at IS NULL OR next_fetch_at <= NOW())
+ORDER BY next_fetch_at ASC NULLS FIRST
 `
 
 func (q *Queries) GetFeedstoFetch(ctx context.Context) ([]Feed, error) {
@@ -34,6 +35,21 @@ func (q *Queries) GetFeedstoFetch(ctx context.Context) ([]Feed, error) {
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.LastError,
+			&i.FailureCount,
+			&i.LastSuccessAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.FetchIntervalSeconds,
+			&i.NextFetchAt,
+			&i.Disabled,
+			&i.Title,
+			&i.SiteUrl,
+			&i.Description,
+			&i.ImageUrl,
+			&i.Language,
+			&i.ParseMode,
+			&i.PostponeCount,
 		); err != nil {
 			return nil, err
 		}
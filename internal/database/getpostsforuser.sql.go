@@ -15,7 +15,7 @@ import (
 
 const getPostsForUser = `-- name: GetPostsForUser :many
 SELECT 
-    p.id, p.created_at, p.updated_at, title, p.url, description, published_at, feed_id, f.id, f.created_at, f.updated_at, name, f.url, user_id, last_fetched_at 
+    p.id, p.created_at, p.updated_at, p.title, p.url, p.description, published_at, feed_id, guid, revision_count, content, author, comments_url, ingest_version, f.id, f.created_at, f.updated_at, name, f.url, user_id, last_fetched_at, last_error, failure_count, last_success_at, etag, last_modified, fetch_interval_seconds, next_fetch_at, disabled, f.title, site_url, f.description, image_url, language, parse_mode, postpone_count 
 FROM posts p
 JOIN feeds f ON p.feed_id = f.id
 WHERE f.user_id = $1
@@ -28,21 +28,42 @@ type GetPostsForUserParams struct {
 }
 
 type GetPostsForUserRow struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Title         string
-	Url           string
-	Description   sql.NullString
-	PublishedAt   time.Time
-	FeedID        uuid.UUID
-	ID_2          uuid.UUID
-	CreatedAt_2   time.Time
-	UpdatedAt_2   time.Time
-	Name          string
-	Url_2         string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Title                string
+	Url                  string
+	Description          sql.NullString
+	PublishedAt          time.Time
+	FeedID               uuid.UUID
+	Guid                 string
+	RevisionCount        int32
+	Content              sql.NullString
+	Author               sql.NullString
+	CommentsUrl          sql.NullString
+	IngestVersion        int32
+	ID_2                 uuid.UUID
+	CreatedAt_2          time.Time
+	UpdatedAt_2          time.Time
+	Name                 string
+	Url_2                string
+	UserID               uuid.UUID
+	LastFetchedAt        sql.NullTime
+	LastError            sql.NullString
+	FailureCount         int32
+	LastSuccessAt        sql.NullTime
+	Etag                 sql.NullString
+	LastModified         sql.NullString
+	FetchIntervalSeconds int32
+	NextFetchAt          sql.NullTime
+	Disabled             bool
+	Title_2              sql.NullString
+	SiteUrl              sql.NullString
+	Description_2        sql.NullString
+	ImageUrl             sql.NullString
+	Language             sql.NullString
+	ParseMode            sql.NullString
+	PostponeCount        int32
 }
 
 func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
@@ -63,6 +84,12 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Guid,
+			&i.RevisionCount,
+			&i.Content,
+			&i.Author,
+			&i.CommentsUrl,
+			&i.IngestVersion,
 			&i.ID_2,
 			&i.CreatedAt_2,
 			&i.UpdatedAt_2,
@@ -70,6 +97,21 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Url_2,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.LastError,
+			&i.FailureCount,
+			&i.LastSuccessAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.FetchIntervalSeconds,
+			&i.NextFetchAt,
+			&i.Disabled,
+			&i.Title_2,
+			&i.SiteUrl,
+			&i.Description_2,
+			&i.ImageUrl,
+			&i.Language,
+			&i.ParseMode,
+			&i.PostponeCount,
 		); err != nil {
 			return nil, err
 		}
@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
@@ -14,11 +15,33 @@ import (
 const markFeedFetched = `-- name: MarkFeedFetched :exec
 UPDATE feeds
     SET last_fetched_at = NOW(),
-        updated_at = NOW()
+        updated_at = NOW(),
+        last_success_at = NOW(),
+        last_error = NULL,
+        failure_count = 0,
+        postpone_count = 0,
+        etag = $2,
+        last_modified = $3,
+        fetch_interval_seconds = $4,
+        next_fetch_at = $5
 WHERE id = $1
 `
 
-func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, markFeedFetched, id)
+type MarkFeedFetchedParams struct {
+	ID                   uuid.UUID
+	Etag                 sql.NullString
+	LastModified         sql.NullString
+	FetchIntervalSeconds int32
+	NextFetchAt          sql.NullTime
+}
+
+func (q *Queries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) error {
+	_, err := q.db.ExecContext(ctx, markFeedFetched,
+		arg.ID,
+		arg.Etag,
+		arg.LastModified,
+		arg.FetchIntervalSeconds,
+		arg.NextFetchAt,
+	)
 	return err
 }
@@ -12,13 +12,28 @@ import (
 )
 
 type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Name                 string
+	Url                  string
+	UserID               uuid.UUID
+	LastFetchedAt        sql.NullTime
+	LastError            sql.NullString
+	FailureCount         int32
+	LastSuccessAt        sql.NullTime
+	Etag                 sql.NullString
+	LastModified         sql.NullString
+	FetchIntervalSeconds int32
+	NextFetchAt          sql.NullTime
+	Disabled             bool
+	Title                sql.NullString
+	SiteUrl              sql.NullString
+	Description          sql.NullString
+	ImageUrl             sql.NullString
+	Language             sql.NullString
+	ParseMode            sql.NullString
+	PostponeCount        int32
 }
 
 type FeedFollow struct {
@@ -29,15 +44,46 @@ type FeedFollow struct {
 	UserID    uuid.UUID
 }
 
+type PodcastEpisode struct {
+	PostID          uuid.UUID
+	MediaUrl        string
+	MediaType       sql.NullString
+	MediaLength     sql.NullInt64
+	DurationSeconds sql.NullInt32
+	Episode         sql.NullInt32
+	Season          sql.NullInt32
+	ImageUrl        sql.NullString
+	Explicit        bool
+}
+
 type Post struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt time.Time
-	FeedID      uuid.UUID
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Title         string
+	Url           string
+	Description   sql.NullString
+	PublishedAt   time.Time
+	FeedID        uuid.UUID
+	Guid          string
+	RevisionCount int32
+	Content       sql.NullString
+	Author        sql.NullString
+	CommentsUrl   sql.NullString
+	IngestVersion int32
+}
+
+type PostCategory struct {
+	PostID uuid.UUID
+	Name   string
+}
+
+type PostEnclosure struct {
+	ID     uuid.UUID
+	PostID uuid.UUID
+	Url    string
+	Type   sql.NullString
+	Length sql.NullInt64
 }
 
 type User struct {
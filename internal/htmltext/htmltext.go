@@ -0,0 +1,336 @@
+package htmltext
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// minWrapWidth keeps deeply nested lists readable on narrow terminals.
+const minWrapWidth = 20
+
+type list struct {
+	ordered bool
+	next    int
+}
+
+// mark is the marker of an inline element like <strong>, and whether it
+// has been written out in the current block yet.
+type mark struct {
+	marker string
+	open   bool
+}
+
+type renderer struct {
+	width int
+	out   strings.Builder
+	// inline collects the text of the block being rendered, it is wrapped
+	// when the block ends.
+	inline strings.Builder
+	// prefix starts every line, for quotes and the indent of list items.
+	prefix string
+	// bullet replaces the end of the prefix on the first line of a list
+	// item.
+	bullet string
+	lists  []list
+	links  []string
+	// marks are the markers of the inline elements being rendered. They
+	// are written just before the first text that needs them and closed at
+	// the end of every block, so a marker never spans two blocks.
+	marks []mark
+	pre   bool
+	// gap asks for a blank line before the next block even inside a list,
+	// to set the list apart from the text before it.
+	gap bool
+}
+
+// Render turns an HTML fragment, like a post description, into plain text
+// for the terminal. Paragraphs, headings, lists, quotes and preformatted
+// text keep their shape, *strong* and _emphasized_ text are marked, and
+// links are numbered and listed as footnotes at the end. Lines are wrapped
+// at width columns, or not at all if width is zero.
+func Render(fragment string, width int) string {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), body)
+	if err != nil {
+		return fragment
+	}
+
+	r := &renderer{width: width}
+	for _, node := range nodes {
+		r.walk(node)
+	}
+	r.endBlock()
+
+	text := strings.TrimRight(r.out.String(), "\n")
+	if len(r.links) > 0 {
+		var notes strings.Builder
+		for i, link := range r.links {
+			fmt.Fprintf(&notes, "\n[%d] %s", i+1, link)
+		}
+		text += "\n" + notes.String()
+	}
+
+	return strings.TrimLeft(text, "\n")
+}
+
+func (r *renderer) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		if r.pre {
+			r.text(n.Data)
+		} else {
+			// Newlines in the source are just spaces, only <br> breaks a line.
+			r.text(strings.NewReplacer("\r", " ", "\n", " ").Replace(n.Data))
+		}
+		return
+	case html.ElementNode:
+	default:
+		r.children(n)
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Head, atom.Title, atom.Noscript, atom.Iframe, atom.Object, atom.Template:
+	case atom.Br:
+		r.inline.WriteString("\n")
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer, atom.Aside,
+		atom.Figure, atom.Figcaption, atom.Table, atom.Tr, atom.Dl, atom.Dt, atom.Dd, atom.Address:
+		r.endBlock()
+		r.children(n)
+		r.endBlock()
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		r.endBlock()
+		r.wrapped(n, "*")
+		r.endBlock()
+	case atom.Blockquote:
+		r.endBlock()
+		prefix := r.prefix
+		r.prefix += "> "
+		r.children(n)
+		r.endBlock()
+		r.prefix = prefix
+	case atom.Ul, atom.Ol:
+		r.endBlock()
+		if len(r.lists) == 0 {
+			r.gap = true
+		}
+		r.lists = append(r.lists, list{ordered: n.DataAtom == atom.Ol, next: 1})
+		r.children(n)
+		r.endBlock()
+		r.lists = r.lists[:len(r.lists)-1]
+	case atom.Li:
+		r.listItem(n)
+	case atom.Pre:
+		r.endBlock()
+		r.pre = true
+		r.children(n)
+		r.endBlock()
+		r.pre = false
+	case atom.Hr:
+		r.endBlock()
+		r.inline.WriteString("----")
+		r.endBlock()
+	case atom.Strong, atom.B:
+		r.wrapped(n, "*")
+	case atom.Em, atom.I, atom.Cite:
+		r.wrapped(n, "_")
+	case atom.Code:
+		if r.pre {
+			r.children(n)
+		} else {
+			r.wrapped(n, "`")
+		}
+	case atom.A:
+		r.children(n)
+		r.link(attr(n, "href"))
+	case atom.Img:
+		if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+			r.text(" [image: " + alt + "] ")
+		}
+	case atom.Td, atom.Th:
+		r.children(n)
+		r.inline.WriteString(" ")
+	default:
+		r.children(n)
+	}
+}
+
+func (r *renderer) children(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		r.walk(child)
+	}
+}
+
+// wrapped renders n's children between a pair of markers, like *this*.
+// Elements with no text in them leave nothing behind.
+func (r *renderer) wrapped(n *html.Node, marker string) {
+	r.marks = append(r.marks, mark{marker: marker})
+	r.children(n)
+
+	last := r.marks[len(r.marks)-1]
+	r.marks = r.marks[:len(r.marks)-1]
+	if last.open {
+		r.inline.WriteString(marker)
+	}
+}
+
+// text adds text to the current block, opening any markers that are still
+// waiting for some.
+func (r *renderer) text(s string) {
+	if strings.TrimSpace(s) != "" {
+		for i := range r.marks {
+			if !r.marks[i].open {
+				r.inline.WriteString(r.marks[i].marker)
+				r.marks[i].open = true
+			}
+		}
+	}
+
+	r.inline.WriteString(s)
+}
+
+// closeMarks ends the markers that are open at the end of a block. They
+// open again if the element has more text in the next one.
+func (r *renderer) closeMarks() {
+	for i := len(r.marks) - 1; i >= 0; i-- {
+		if r.marks[i].open {
+			r.inline.WriteString(r.marks[i].marker)
+			r.marks[i].open = false
+		}
+	}
+}
+
+// link numbers a link and notes it down for the footnotes. Links within
+// the page and javascript: ones are left out, they are no use in a terminal.
+func (r *renderer) link(href string) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return
+	}
+
+	for i, link := range r.links {
+		if link == href {
+			fmt.Fprintf(&r.inline, "[%d]", i+1)
+			return
+		}
+	}
+
+	r.links = append(r.links, href)
+	fmt.Fprintf(&r.inline, "[%d]", len(r.links))
+}
+
+func (r *renderer) listItem(n *html.Node) {
+	r.endBlock()
+
+	bullet := "- "
+	if len(r.lists) > 0 && r.lists[len(r.lists)-1].ordered {
+		current := &r.lists[len(r.lists)-1]
+		bullet = fmt.Sprintf("%d. ", current.next)
+		current.next++
+	}
+
+	prefix := r.prefix
+	r.prefix += strings.Repeat(" ", len(bullet))
+	r.bullet = bullet
+	r.children(n)
+	r.endBlock()
+	r.prefix = prefix
+	r.bullet = ""
+}
+
+// endBlock writes out the text collected for the current block. Blocks are
+// separated by a blank line, apart from those inside lists, which are kept
+// together.
+func (r *renderer) endBlock() {
+	r.closeMarks()
+	text := r.inline.String()
+	r.inline.Reset()
+
+	var lines []string
+	if r.pre {
+		lines = strings.Split(strings.Trim(text, "\n"), "\n")
+	} else {
+		for _, line := range strings.Split(text, "\n") {
+			lines = append(lines, wrap(strings.Fields(line), r.lineWidth())...)
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	if r.out.Len() > 0 && (len(r.lists) == 0 || r.gap) {
+		r.out.WriteString("\n")
+	}
+	r.gap = false
+
+	for i, line := range lines {
+		prefix := r.prefix
+		if i == 0 && r.bullet != "" {
+			prefix = prefix[:len(prefix)-len(r.bullet)] + r.bullet
+		}
+		r.out.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+	}
+	r.bullet = ""
+}
+
+func (r *renderer) lineWidth() int {
+	if r.width <= 0 {
+		return 0
+	}
+
+	width := r.width - utf8.RuneCountInString(r.prefix)
+	if width < minWrapWidth {
+		return minWrapWidth
+	}
+
+	return width
+}
+
+// wrap fills lines with words up to width runes. Words longer than a line,
+// like URLs, get a line of their own rather than being split.
+func wrap(words []string, width int) []string {
+	if len(words) == 0 {
+		return []string{""}
+	}
+	if width <= 0 {
+		return []string{strings.Join(words, " ")}
+	}
+
+	var lines []string
+	line := words[0]
+	length := utf8.RuneCountInString(line)
+	for _, word := range words[1:] {
+		wordLength := utf8.RuneCountInString(word)
+		if length+1+wordLength > width {
+			lines = append(lines, line)
+			line, length = word, wordLength
+			continue
+		}
+
+		line += " " + word
+		length += 1 + wordLength
+	}
+
+	return append(lines, line)
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+
+	return ""
+}
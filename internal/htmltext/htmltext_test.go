@@ -0,0 +1,87 @@
+package htmltext
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		width    int
+		expected string
+	}{
+		{
+			name:     "plain text",
+			input:    "Just some   text\nover two lines",
+			expected: "Just some text over two lines",
+		},
+		{
+			name:     "paragraphs and line breaks",
+			input:    "<p>First paragraph.</p><p>Second<br>line two</p>",
+			expected: "First paragraph.\n\nSecond\nline two",
+		},
+		{
+			name:     "emphasis and code",
+			input:    "<p>This is <strong>bold</strong>, <em>leaning</em> and <code>go vet</code>.</p>",
+			expected: "This is *bold*, _leaning_ and `go vet`.",
+		},
+		{
+			name:     "links become footnotes",
+			input:    `<p>Read <a href="https://example.com/a">this</a> and <a href="https://example.com/b">that</a>, then <a href="https://example.com/a">this again</a>.</p>`,
+			expected: "Read this[1] and that[2], then this again[1].\n\n[1] https://example.com/a\n[2] https://example.com/b",
+		},
+		{
+			name:     "anchors and scripts are dropped",
+			input:    `<p><a href="#top">Top</a><script>alert(1)</script><style>p {}</style></p>`,
+			expected: "Top",
+		},
+		{
+			name:     "lists",
+			input:    "<p>Steps:</p><ol><li>One</li><li>Two<ul><li>Nested</li></ul></li></ol><p>Done</p>",
+			expected: "Steps:\n\n1. One\n2. Two\n   - Nested\n\nDone",
+		},
+		{
+			name:     "images and quotes",
+			input:    `<blockquote><p>Quoted <img src="x.png" alt="a cat"></p></blockquote><img src="pixel.gif" width="1" height="1">`,
+			expected: "> Quoted [image: a cat]",
+		},
+		{
+			name:     "preformatted text keeps its layout",
+			input:    "<pre><code>if x {\n    y()\n}</code></pre>",
+			width:    10,
+			expected: "if x {\n    y()\n}",
+		},
+		{
+			name:     "wrapping",
+			input:    "<p>The quick brown fox jumps over the lazy dog</p><ul><li>The quick brown fox jumps over the lazy dog</li></ul>",
+			width:    30,
+			expected: "The quick brown fox jumps over\nthe lazy dog\n\n- The quick brown fox jumps\n  over the lazy dog",
+		},
+		{
+			name:     "blocks inside emphasis",
+			input:    "<strong><p>Hello</p></strong><h2><p>Title</p></h2><em>a<div>b</div></em>",
+			expected: "*Hello*\n\n*Title*\n\n_a_\n\n_b_",
+		},
+		{
+			name:     "list inside emphasis",
+			input:    "<b>y<ul><li>z</li></ul></b>",
+			expected: "*y*\n\n- *z*",
+		},
+		{
+			name:     "empty emphasis",
+			input:    "<p>x <b></b> y <b> </b>z</p>",
+			expected: "x y z",
+		},
+		{
+			name:     "entities",
+			input:    "<p>Tom &amp; Jerry &lt;3</p>",
+			expected: "Tom & Jerry <3",
+		},
+	}
+
+	for _, c := range cases {
+		actual := Render(c.input, c.width)
+		if actual != c.expected {
+			t.Errorf("%s: Expected %q but got %q", c.name, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package rss
+
+import (
+	"encoding/xml"
+	"html"
+	"strconv"
+	"strings"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type AtomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	Base     string      `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title    AtomText    `xml:"title"`
+	Subtitle AtomText    `xml:"subtitle"`
+	Links    []AtomLink  `xml:"link"`
+	Logo     string      `xml:"logo"`
+	Icon     string      `xml:"icon"`
+	Lang     string      `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Entries  []AtomEntry `xml:"entry"`
+}
+
+type AtomEntry struct {
+	Base       string         `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	ID         string         `xml:"id"`
+	Title      AtomText       `xml:"title"`
+	Links      []AtomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    AtomText       `xml:"summary"`
+	Content    AtomText       `xml:"content"`
+	Authors    []AtomPerson   `xml:"author"`
+	Categories []AtomCategory `xml:"category"`
+}
+
+type AtomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type AtomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email"`
+}
+
+type AtomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+// AtomText is an Atom text construct. Its type is "text", "html" or
+// "xhtml", and xhtml content is inline markup rather than character data.
+type AtomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// UnmarshalXML skips elements from other namespaces, which Go would match
+// by their local name alone, so <media:title> can't stand in for <title>.
+// Elements with no namespace are Atom too, for feeds that forget xmlns.
+func (t *AtomText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if start.Name.Space != atomNamespace && start.Name.Space != "" {
+		return d.Skip()
+	}
+
+	type plain AtomText
+	return d.DecodeElement((*plain)(t), &start)
+}
+
+func (t AtomText) value() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.Inner)
+	}
+
+	return strings.TrimSpace(t.Text)
+}
+
+// plain returns the text with any html escaping undone, for fields like
+// titles that are shown as-is.
+func (t AtomText) plain() string {
+	if t.Type == "html" || t.Type == "xhtml" {
+		return html.UnescapeString(t.value())
+	}
+
+	return t.value()
+}
+
+func parseAtom(decoder *xml.Decoder) (*Feed, error) {
+	var feed AtomFeed
+	err := decoder.Decode(&feed)
+	if err != nil {
+		return nil, err
+	}
+
+	return feed.normalize(), nil
+}
+
+func (f *AtomFeed) normalize() *Feed {
+	feed := &Feed{
+		Title:       f.Title.plain(),
+		Link:        alternateLink(f.Links),
+		Description: f.Subtitle.plain(),
+		Image:       strings.TrimSpace(f.Logo),
+		Language:    strings.TrimSpace(f.Lang),
+		base:        strings.TrimSpace(f.Base),
+	}
+	if feed.Image == "" {
+		feed.Image = strings.TrimSpace(f.Icon)
+	}
+
+	for _, entry := range f.Entries {
+		description := entry.Summary.value()
+		if description == "" {
+			description = entry.Content.value()
+		}
+
+		var authors []string
+		for _, author := range entry.Authors {
+			name := strings.TrimSpace(author.Name)
+			if name == "" {
+				name = strings.TrimSpace(author.Email)
+			}
+			if name != "" {
+				authors = append(authors, name)
+			}
+		}
+
+		var categories []string
+		for _, category := range entry.Categories {
+			term := strings.TrimSpace(category.Label)
+			if term == "" {
+				term = strings.TrimSpace(category.Term)
+			}
+			if term != "" {
+				categories = append(categories, term)
+			}
+		}
+
+		var comments string
+		var enclosures []Enclosure
+		for _, link := range entry.Links {
+			switch link.Rel {
+			case "enclosure":
+				length, _ := strconv.ParseInt(strings.TrimSpace(link.Length), 10, 64)
+				enclosures = append(enclosures, Enclosure{
+					URL:    strings.TrimSpace(link.Href),
+					Type:   link.Type,
+					Length: length,
+				})
+			case "replies":
+				if comments == "" {
+					comments = strings.TrimSpace(link.Href)
+				}
+			}
+		}
+
+		feed.Items = append(feed.Items, Item{
+			ID:          strings.TrimSpace(entry.ID),
+			Title:       entry.Title.plain(),
+			Link:        alternateLink(entry.Links),
+			Description: description,
+			Content:     entry.Content.value(),
+			Author:      strings.Join(authors, ", "),
+			Categories:  categories,
+			Comments:    comments,
+			Enclosures:  enclosures,
+			PubDate:     strings.TrimSpace(entry.Published),
+			Updated:     strings.TrimSpace(entry.Updated),
+			Base:        strings.TrimSpace(entry.Base),
+		})
+	}
+
+	return feed
+}
+
+// alternateLink picks the link that points at the page itself. A link with
+// no rel is an alternate link too, and if there is none at all the first
+// link is better than nothing.
+func alternateLink(links []AtomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+
+	if len(links) > 0 {
+		return links[0].Href
+	}
+
+	return ""
+}
@@ -0,0 +1,193 @@
+package rss
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestParseAtom(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		expected Feed
+	}{
+		{
+			name: "minimal",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Example</title>
+	<link href="https://example.com/"/>
+	<entry>
+		<id>urn:example:1</id>
+		<title>First</title>
+		<link href="https://example.com/first"/>
+		<updated>2024-05-01T10:00:00Z</updated>
+		<summary>The first entry</summary>
+	</entry>
+</feed>`,
+			expected: Feed{
+				Title: "Example",
+				Link:  "https://example.com/",
+				Items: []Item{
+					{
+						ID:          "urn:example:1",
+						Title:       "First",
+						Link:        "https://example.com/first",
+						Description: "The first entry",
+						Updated:     "2024-05-01T10:00:00Z",
+					},
+				},
+			},
+		},
+		{
+			name: "escaped html, xhtml content and extra links",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="en-GB">
+	<title type="html">Tom &amp;amp; Jerry</title>
+	<subtitle type="html">&lt;em&gt;Cartoons&lt;/em&gt;</subtitle>
+	<link rel="self" href="https://example.com/atom.xml"/>
+	<link rel="alternate" type="text/html" href="https://example.com/"/>
+	<icon>https://example.com/favicon.ico</icon>
+	<entry>
+		<id>urn:example:2</id>
+		<title type="text">Chase &amp; run</title>
+		<link rel="replies" href="https://example.com/2#comments"/>
+		<link rel="enclosure" href="https://example.com/2.mp3" type="audio/mpeg" length="2048"/>
+		<link rel="alternate" href="https://example.com/2"/>
+		<published>2024-05-02T10:00:00Z</published>
+		<author><email>tom@example.com</email></author>
+		<author><name>Jerry</name></author>
+		<category term="cats" label="Cats"/>
+		<category term="mice"/>
+		<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Hi <b>there</b></p></div></content>
+	</entry>
+</feed>`,
+			expected: Feed{
+				Title:       "Tom & Jerry",
+				Link:        "https://example.com/",
+				Description: "<em>Cartoons</em>",
+				Image:       "https://example.com/favicon.ico",
+				Language:    "en-GB",
+				Items: []Item{
+					{
+						ID:          "urn:example:2",
+						Title:       "Chase & run",
+						Link:        "https://example.com/2",
+						Description: `<div xmlns="http://www.w3.org/1999/xhtml"><p>Hi <b>there</b></p></div>`,
+						Content:     `<div xmlns="http://www.w3.org/1999/xhtml"><p>Hi <b>there</b></p></div>`,
+						Author:      "tom@example.com, Jerry",
+						Categories:  []string{"Cats", "mice"},
+						Comments:    "https://example.com/2#comments",
+						Enclosures:  []Enclosure{{URL: "https://example.com/2.mp3", Type: "audio/mpeg", Length: 2048}},
+						PubDate:     "2024-05-02T10:00:00Z",
+					},
+				},
+			},
+		},
+		{
+			name: "media siblings",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+	<title>Example</title>
+	<entry>
+		<id>urn:example:3</id>
+		<title>The real title</title>
+		<content type="html">&lt;p&gt;The real content&lt;/p&gt;</content>
+		<media:content url="https://example.com/3.jpg" medium="image"/>
+		<media:title>A caption</media:title>
+		<media:description>About the picture</media:description>
+	</entry>
+</feed>`,
+			expected: Feed{
+				Title: "Example",
+				Items: []Item{
+					{
+						ID:          "urn:example:3",
+						Title:       "The real title",
+						Description: "<p>The real content</p>",
+						Content:     "<p>The real content</p>",
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := parseAtom(newXMLDecoder([]byte(c.body)))
+		if err != nil {
+			t.Errorf("%s: Expected no error but got %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(*actual, c.expected) {
+			t.Errorf("%s: Expected %+v but got %+v", c.name, c.expected, *actual)
+		}
+	}
+}
+
+func TestAlternateLink(t *testing.T) {
+	cases := []struct {
+		input    []AtomLink
+		expected string
+	}{
+		{
+			input:    []AtomLink{{Href: "https://example.com/self", Rel: "self"}, {Href: "https://example.com/", Rel: "alternate"}},
+			expected: "https://example.com/",
+		},
+		{
+			input:    []AtomLink{{Href: "https://example.com/self", Rel: "self"}, {Href: "https://example.com/"}},
+			expected: "https://example.com/",
+		},
+		{
+			input:    []AtomLink{{Href: "https://example.com/self", Rel: "self"}, {Href: "https://example.com/related", Rel: "related"}},
+			expected: "https://example.com/self",
+		},
+		{
+			input:    nil,
+			expected: "",
+		},
+	}
+
+	for _, c := range cases {
+		actual := alternateLink(c.input)
+		if actual != c.expected {
+			t.Errorf("Expected %q but got %q for %+v", c.expected, actual, c.input)
+		}
+	}
+}
+
+func TestAtomText(t *testing.T) {
+	cases := []struct {
+		input string
+		value string
+		plain string
+	}{
+		{
+			input: `<title>  Tom &amp; Jerry  </title>`,
+			value: "Tom & Jerry",
+			plain: "Tom & Jerry",
+		},
+		{
+			input: `<title type="html">&lt;b&gt;Tom&lt;/b&gt; &amp;amp; Jerry</title>`,
+			value: "<b>Tom</b> &amp; Jerry",
+			plain: "<b>Tom</b> & Jerry",
+		},
+		{
+			input: `<title type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml">Tom &amp; <b>Jerry</b></div></title>`,
+			value: `<div xmlns="http://www.w3.org/1999/xhtml">Tom &amp; <b>Jerry</b></div>`,
+			plain: `<div xmlns="http://www.w3.org/1999/xhtml">Tom & <b>Jerry</b></div>`,
+		},
+	}
+
+	for _, c := range cases {
+		var text AtomText
+		err := xml.Unmarshal([]byte(c.input), &text)
+		if err != nil {
+			t.Errorf("Expected no error but got %v for %s", err, c.input)
+			continue
+		}
+		if text.value() != c.value {
+			t.Errorf("Expected value %q but got %q for %s", c.value, text.value(), c.input)
+		}
+		if text.plain() != c.plain {
+			t.Errorf("Expected plain %q but got %q for %s", c.plain, text.plain(), c.input)
+		}
+	}
+}
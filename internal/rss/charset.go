@@ -0,0 +1,81 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+var xmlDeclEncoding = regexp.MustCompile(`^\s*<\?xml[^>]*?\sencoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+
+// toUTF8 converts an XML feed to UTF-8. A byte order mark decides the
+// charset if there is one, then the charset in the Content-Type header, then
+// the encoding in the XML declaration, and UTF-8 is assumed otherwise.
+func toUTF8(body []byte, contentType string) ([]byte, error) {
+	label := feedCharset(body, contentType)
+	if label == "" {
+		return body, nil
+	}
+
+	encoding, name := charset.Lookup(label)
+	if encoding == nil {
+		return nil, fmt.Errorf("unsupported charset: %q", label)
+	}
+	if name == "utf-8" {
+		return body, nil
+	}
+
+	converted, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %v", name, err)
+	}
+
+	// A UTF-16 byte order mark comes through as a UTF-8 one.
+	return bytes.TrimPrefix(converted, []byte("\xef\xbb\xbf")), nil
+}
+
+func feedCharset(body []byte, contentType string) string {
+	switch {
+	case bytes.HasPrefix(body, []byte("\xef\xbb\xbf")):
+		return "utf-8"
+	case bytes.HasPrefix(body, []byte("\xfe\xff")):
+		return "utf-16be"
+	case bytes.HasPrefix(body, []byte("\xff\xfe")):
+		return "utf-16le"
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err == nil && strings.TrimSpace(params["charset"]) != "" {
+		return strings.TrimSpace(params["charset"])
+	}
+
+	// The declaration has to be on the first line, so there is no need to
+	// look further than that.
+	head := body
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	match := xmlDeclEncoding.FindSubmatch(head)
+	if match != nil {
+		return string(match[1])
+	}
+
+	return ""
+}
+
+// newXMLDecoder returns a decoder for a body that toUTF8 has already
+// converted, so the encoding its declaration names no longer applies.
+func newXMLDecoder(body []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	return decoder
+}
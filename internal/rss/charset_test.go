@@ -0,0 +1,79 @@
+package rss
+
+import "testing"
+
+func TestParseFeedCharset(t *testing.T) {
+	cases := []struct {
+		name        string
+		body        string
+		contentType string
+		expected    string
+	}{
+		{
+			name:     "ISO-8859-1 declaration",
+			body:     "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><rss><channel><item><title>Caf\xe9</title></item></channel></rss>",
+			expected: "Café",
+		},
+		{
+			name:     "windows-1252 declaration",
+			body:     "<?xml version='1.0' encoding='windows-1252'?><rss><channel><item><title>\x93quoted\x94 \x80</title></item></channel></rss>",
+			expected: "“quoted” €",
+		},
+		{
+			name:     "Shift_JIS declaration",
+			body:     "<?xml version=\"1.0\" encoding=\"Shift_JIS\"?><rss><channel><item><title>\x83j\x83\x85\x81[\x83X</title></item></channel></rss>",
+			expected: "ニュース",
+		},
+		{
+			name:        "Content-Type charset",
+			body:        "<rss><channel><item><title>Caf\xe9</title></item></channel></rss>",
+			contentType: "application/rss+xml; charset=iso-8859-1",
+			expected:    "Café",
+		},
+		{
+			name:        "Content-Type wins over the declaration",
+			body:        "<?xml version=\"1.0\" encoding=\"UTF-8\"?><rss><channel><item><title>Caf\xe9</title></item></channel></rss>",
+			contentType: "text/xml; charset=ISO-8859-1",
+			expected:    "Café",
+		},
+		{
+			name:     "UTF-8 with byte order mark",
+			body:     "\xef\xbb\xbf<?xml version=\"1.0\" encoding=\"UTF-8\"?><rss><channel><item><title>Café</title></item></channel></rss>",
+			expected: "Café",
+		},
+		{
+			name:     "UTF-16 with byte order mark",
+			body:     utf16LE("\ufeff<?xml version=\"1.0\" encoding=\"UTF-16\"?><rss><channel><item><title>Café</title></item></channel></rss>"),
+			expected: "Café",
+		},
+	}
+
+	for _, c := range cases {
+		feed, err := parseFeed([]byte(c.body), c.contentType, "")
+		if err != nil {
+			t.Errorf("%s: Expected no error but got %v", c.name, err)
+			continue
+		}
+
+		if len(feed.Items) != 1 || feed.Items[0].Title != c.expected {
+			t.Errorf("%s: Expected %q but got %+v", c.name, c.expected, feed.Items)
+		}
+	}
+}
+
+func TestParseFeedUnknownCharset(t *testing.T) {
+	body := []byte(`<?xml version="1.0" encoding="x-made-up"?><rss><channel></channel></rss>`)
+
+	_, err := parseFeed(body, "", "")
+	if err == nil {
+		t.Errorf("Expected an error but got none")
+	}
+}
+
+func utf16LE(s string) string {
+	var b []byte
+	for _, r := range s {
+		b = append(b, byte(r), byte(r>>8))
+	}
+	return string(b)
+}
@@ -0,0 +1,153 @@
+package rss
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// dateLayouts are tried in order by ParseDate. Weekdays are stripped before
+// parsing, since they are often missing, misspelled or plain wrong, so none
+// of these start with one.
+var dateLayouts = []string{
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 January 2006 15:04:05 -0700",
+	"2 January 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006",
+	"Jan 2, 2006 15:04:05 -0700",
+	"Jan 2, 2006 3:04 PM -0700",
+	"Jan 2, 2006",
+	"January 2, 2006 15:04:05 -0700",
+	"January 2, 2006",
+	"02-Jan-06 15:04:05 -0700",
+	"Jan 2 15:04:05 2006",
+	"Jan 2 15:04:05 -0700 2006",
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// zoneOffsets covers the zone names that show up in feed dates. Go only
+// knows the offset of a zone name if it belongs to the local time zone and
+// silently treats every other one as UTC, so they are swapped for numeric
+// offsets before parsing.
+var zoneOffsets = map[string]string{
+	"UT":   "+0000",
+	"UTC":  "+0000",
+	"GMT":  "+0000",
+	"Z":    "+0000",
+	"EST":  "-0500",
+	"EDT":  "-0400",
+	"CST":  "-0600",
+	"CDT":  "-0500",
+	"MST":  "-0700",
+	"MDT":  "-0600",
+	"PST":  "-0800",
+	"PDT":  "-0700",
+	"AKST": "-0900",
+	"AKDT": "-0800",
+	"HST":  "-1000",
+	"WET":  "+0000",
+	"WEST": "+0100",
+	"BST":  "+0100",
+	"CET":  "+0100",
+	"CEST": "+0200",
+	"MET":  "+0100",
+	"MEST": "+0200",
+	"EET":  "+0200",
+	"EEST": "+0300",
+	"MSK":  "+0300",
+	"IST":  "+0530",
+	"SGT":  "+0800",
+	"HKT":  "+0800",
+	"JST":  "+0900",
+	"KST":  "+0900",
+	"AEST": "+1000",
+	"AEDT": "+1100",
+	"NZST": "+1200",
+	"NZDT": "+1300",
+}
+
+// ParseDate parses a publication date in any of the layouts feeds use in
+// practice: RFC 822/1123 with numeric or named zones, RFC 3339 and ISO 8601
+// variants, with or without weekdays and leading zeros. Dates without a
+// zone are taken to be UTC.
+func ParseDate(value string) (time.Time, error) {
+	value = normalizeDate(value)
+	if value == "" {
+		return time.Time{}, errors.New("empty date")
+	}
+
+	for _, layout := range dateLayouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognised date format: %q", value)
+}
+
+func normalizeDate(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	// Drop the weekday, "Tue," and "Tuesday," alike.
+	if strings.HasSuffix(fields[0], ",") && isLetters(strings.TrimSuffix(fields[0], ",")) {
+		fields = fields[1:]
+	} else if len(fields) > 1 && isLetters(fields[0]) && isLetters(fields[1]) {
+		// ANSIC and Unix dates have no comma: "Mon Jan 2 15:04:05 2006".
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+
+	// "Jan 2 15:04:05 MST 2006" has the zone in front of the year.
+	for i, field := range fields {
+		if offset, ok := zoneOffsets[strings.ToUpper(field)]; ok && i > 0 {
+			fields[i] = offset
+		}
+	}
+
+	// "+0000 (UTC)" style comments after the zone.
+	last := fields[len(fields)-1]
+	if strings.HasPrefix(last, "(") && strings.HasSuffix(last, ")") {
+		fields = fields[:len(fields)-1]
+	}
+
+	return strings.Join(fields, " ")
+}
+
+func isLetters(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+
+	return true
+}
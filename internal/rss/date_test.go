@@ -0,0 +1,171 @@
+package rss
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected time.Time
+	}{
+		{
+			input:    "Mon, 02 Jan 2006 15:04:05 -0700",
+			expected: time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "Mon, 02 Jan 2006 15:04:05 GMT",
+			expected: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "Mon, 02 Jan 2006 15:04:05 EST",
+			expected: time.Date(2006, 1, 2, 20, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "Tue, 5 Mar 2024 09:30:00 PDT",
+			expected: time.Date(2024, 3, 5, 16, 30, 0, 0, time.UTC),
+		},
+		{
+			input:    "5 Mar 2024 09:30:00 +0100",
+			expected: time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			input:    "Thurs, 07 Mar 2024 09:30:00 +0000",
+			expected: time.Date(2024, 3, 7, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			input:    "Wednesday, 07 Mar 2024 09:30 +0000",
+			expected: time.Date(2024, 3, 7, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			input:    "Mon, 02 Jan 06 15:04:05 -0700",
+			expected: time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "Mon, 2 January 2006 15:04:05 +0000",
+			expected: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "Mon, 02 Jan 2006 15:04:05 +0000 (UTC)",
+			expected: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "  Mon,  02 Jan 2006   15:04:05 +0000 ",
+			expected: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "2006-01-02T15:04:05Z",
+			expected: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "2006-01-02T15:04:05.123+02:00",
+			expected: time.Date(2006, 1, 2, 13, 4, 5, 123000000, time.UTC),
+		},
+		{
+			input:    "2006-01-02T15:04+02:00",
+			expected: time.Date(2006, 1, 2, 13, 4, 0, 0, time.UTC),
+		},
+		{
+			input:    "2006-01-02T15:04:05-0500",
+			expected: time.Date(2006, 1, 2, 20, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "2006-01-02T15:04:05",
+			expected: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "2006-01-02 15:04:05",
+			expected: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "2006-01-02",
+			expected: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			input:    "Jan 2, 2006",
+			expected: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			input:    "January 2, 2006",
+			expected: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			input:    "Mon Jan  2 15:04:05 2006",
+			expected: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "Mon Jan  2 15:04:05 MST 2006",
+			expected: time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "Monday, 02-Jan-06 15:04:05 CET",
+			expected: time.Date(2006, 1, 2, 14, 4, 5, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := ParseDate(c.input)
+		if err != nil {
+			t.Errorf("Expected %q to parse but got %v", c.input, err)
+			continue
+		}
+		if !actual.Equal(c.expected) {
+			t.Errorf("Expected %v but got %v for %q", c.expected, actual.UTC(), c.input)
+		}
+	}
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		"   ",
+		"yesterday",
+		"Mon, 32 Jan 2006 15:04:05 +0000",
+		"2006-13-02",
+	}
+
+	for _, c := range cases {
+		actual, err := ParseDate(c)
+		if err == nil {
+			t.Errorf("Expected %q to fail but got %v", c, actual)
+		}
+	}
+}
+
+func TestItemPublished(t *testing.T) {
+	fallback := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	cases := []struct {
+		item     Item
+		expected time.Time
+		ok       bool
+	}{
+		{
+			item:     Item{PubDate: "Mon, 02 Jan 2006 15:04:05 +0000", Updated: "2010-01-01T00:00:00Z"},
+			expected: fallback,
+			ok:       true,
+		},
+		{
+			item:     Item{PubDate: "not a date", DCDate: "2006-01-02T15:04:05Z"},
+			expected: fallback,
+			ok:       true,
+		},
+		{
+			item:     Item{Updated: "2006-01-02T15:04:05Z"},
+			expected: fallback,
+			ok:       true,
+		},
+		{
+			item: Item{PubDate: "soon"},
+			ok:   false,
+		},
+	}
+
+	for _, c := range cases {
+		actual, ok := c.item.Published()
+		if ok != c.ok || !actual.Equal(c.expected) {
+			t.Errorf("Expected %v, %v but got %v, %v", c.expected, c.ok, actual, ok)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// feedLinkTypes are the <link rel="alternate"> types that point at feeds.
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+	"application/rdf+xml":   true,
+}
+
+// commonFeedPaths are tried, in order, on sites that don't link their feed.
+var commonFeedPaths = []string{
+	"/feed",
+	"/rss.xml",
+	"/atom.xml",
+	"/feed.xml",
+	"/index.xml",
+	"/feed.json",
+}
+
+// DiscoveredFeed is a feed found on a web page. Title and Type are empty
+// when the page didn't say.
+type DiscoveredFeed struct {
+	URL   string
+	Title string
+	Type  string
+}
+
+// Discover looks for the feeds behind a web page. It reads the feeds the
+// page advertises with <link rel="alternate">, and if there are none tries
+// the paths blogs most often use until one of them turns out to be a feed.
+func (f *Fetcher) Discover(ctx context.Context, pageURL string) ([]DiscoveredFeed, error) {
+	req, err := f.newRequest(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := f.readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	base := resp.Request.URL
+	found := feedLinks(body, base)
+	if len(found) > 0 {
+		return found, nil
+	}
+
+	for _, path := range commonFeedPaths {
+		candidate := base.ResolveReference(&url.URL{Path: path}).String()
+		fetched, err := f.Fetch(ctx, candidate, Cache{})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		// Several of the paths are often the same feed, one is enough.
+		return []DiscoveredFeed{{URL: candidate, Title: fetched.Feed.Title}}, nil
+	}
+
+	return nil, nil
+}
+
+// feedLinks returns the feeds a page links to in its <link> elements, with
+// their URLs resolved against the page or its <base href>.
+func feedLinks(page []byte, base *url.URL) []DiscoveredFeed {
+	var found []DiscoveredFeed
+	seen := make(map[string]bool)
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return found
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue
+		}
+
+		token := tokenizer.Token()
+		switch token.DataAtom {
+		case atom.Base:
+			href, err := url.Parse(strings.TrimSpace(attr(token, "href")))
+			if err == nil {
+				base = base.ResolveReference(href)
+			}
+		case atom.Link:
+			if !hasToken(attr(token, "rel"), "alternate") {
+				continue
+			}
+
+			linkType := strings.ToLower(strings.TrimSpace(attr(token, "type")))
+			if !feedLinkTypes[linkType] {
+				continue
+			}
+
+			href, err := url.Parse(strings.TrimSpace(attr(token, "href")))
+			if err != nil || href.String() == "" {
+				continue
+			}
+
+			feedURL := base.ResolveReference(href).String()
+			if seen[feedURL] {
+				continue
+			}
+			seen[feedURL] = true
+
+			found = append(found, DiscoveredFeed{
+				URL:   feedURL,
+				Title: strings.TrimSpace(attr(token, "title")),
+				Type:  linkType,
+			})
+		case atom.Body:
+			// Feed links belong in the head, and pages can be long.
+			return found
+		}
+	}
+}
+
+func attr(token html.Token, name string) string {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+// hasToken reports whether a space separated attribute like rel contains
+// value, ignoring case.
+func hasToken(list, value string) bool {
+	for _, token := range strings.Fields(list) {
+		if strings.EqualFold(token, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package rss
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestFeedLinks(t *testing.T) {
+	base, _ := url.Parse("https://example.com/blog/post")
+
+	cases := []struct {
+		name     string
+		page     string
+		expected []DiscoveredFeed
+	}{
+		{
+			name: "rss and atom",
+			page: `<!DOCTYPE html><html><head>
+				<link rel="stylesheet" href="/style.css">
+				<link rel="alternate" type="application/rss+xml" title="RSS" href="/rss.xml">
+				<link rel="Alternate" type="application/atom+xml" href="https://feeds.example.com/atom">
+				</head><body></body></html>`,
+			expected: []DiscoveredFeed{
+				{URL: "https://example.com/rss.xml", Title: "RSS", Type: "application/rss+xml"},
+				{URL: "https://feeds.example.com/atom", Type: "application/atom+xml"},
+			},
+		},
+		{
+			name: "json feed relative to base",
+			page: `<html><head><base href="https://cdn.example.com/site/">
+				<link rel="alternate feed" type="application/feed+json" href="feed.json" />
+				</head></html>`,
+			expected: []DiscoveredFeed{
+				{URL: "https://cdn.example.com/site/feed.json", Type: "application/feed+json"},
+			},
+		},
+		{
+			name: "duplicates and other alternates",
+			page: `<head>
+				<link rel="alternate" type="application/rss+xml" href="feed">
+				<link rel="alternate" type="application/rss+xml" href="/blog/feed">
+				<link rel="alternate" hreflang="de" href="/de/">
+				<link rel="alternate" type="application/rss+xml" href="">
+				</head>`,
+			expected: []DiscoveredFeed{
+				{URL: "https://example.com/blog/feed", Type: "application/rss+xml"},
+			},
+		},
+		{
+			name: "links in the body are ignored",
+			page: `<html><body><link rel="alternate" type="application/rss+xml" href="/rss.xml"></body></html>`,
+		},
+	}
+
+	for _, c := range cases {
+		actual := feedLinks([]byte(c.page), base)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: Expected %+v but got %+v", c.name, c.expected, actual)
+		}
+	}
+}
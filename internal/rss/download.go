@@ -0,0 +1,71 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Download saves the file at fileURL to path. It is written to path+".part"
+// first and renamed once complete, and a part file left behind by an
+// earlier, interrupted download is resumed with a Range request. Servers
+// that ignore the range send the whole file again and it starts over.
+func (f *Fetcher) Download(ctx context.Context, fileURL, path string) error {
+	partPath := path + ".part"
+
+	var offset int64
+	info, err := os.Stat(partPath)
+	if err == nil {
+		offset = info.Size()
+	}
+
+	req, err := f.newRequest(ctx, fileURL)
+	if err != nil {
+		return err
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := f.downloadClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return fmt.Errorf("unexpected content range: %s", resp.Header.Get("Content-Range"))
+		}
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The part file already holds everything there is.
+		return os.Rename(partPath, path)
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		flags |= os.O_TRUNC
+	default:
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, resp.Body)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	return os.Rename(partPath, path)
+}
@@ -0,0 +1,363 @@
+package rss
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+const (
+	defaultConnectTimeout = 10 * time.Second
+	defaultTimeout        = 30 * time.Second
+	defaultMaxBodySize    = 10 << 20
+	defaultUserAgent      = "Gator RSS Feed Reader"
+	defaultHostMinDelay   = time.Second
+	maxRedirects          = 10
+)
+
+// FetcherOptions configures a Fetcher. Zero values fall back to defaults.
+type FetcherOptions struct {
+	// ConnectTimeout bounds dialing and the TLS handshake.
+	ConnectTimeout time.Duration
+	// Timeout bounds the whole request, including reading the body.
+	Timeout time.Duration
+	// MaxBodySize is the largest feed, after decompression, in bytes.
+	MaxBodySize int64
+	// Proxy is an HTTP proxy URL. Without one the usual HTTP_PROXY and
+	// HTTPS_PROXY environment variables are used.
+	Proxy     string
+	UserAgent string
+	// HostMinDelay is the minimum time between requests to the same host
+	// once HostBurst requests have been made in quick succession.
+	HostMinDelay time.Duration
+	HostBurst    int
+	// AllowedHosts lets feeds on private and loopback addresses through.
+	// Entries are hostnames, IP addresses or CIDR ranges.
+	AllowedHosts []string
+}
+
+// Fetcher downloads and parses feeds. It is safe for concurrent use and
+// should be shared, so connections to the same host are reused.
+type Fetcher struct {
+	client *http.Client
+	// downloadClient has no overall timeout, media files can take a long
+	// time to download and are cancelled through the context instead.
+	downloadClient *http.Client
+	limiter        *hostLimiter
+	guard          *addressGuard
+	viaProxy       bool
+	userAgent      string
+	maxBodySize    int64
+}
+
+// Cache holds the validators from a previous response, which are sent back
+// so the server can answer with 304 Not Modified if nothing changed.
+type Cache struct {
+	ETag         string
+	LastModified string
+}
+
+type FetchResult struct {
+	// Feed is nil when NotModified is set.
+	Feed        *Feed
+	NotModified bool
+	Cache       Cache
+	// PermanentURL is set when the feed was reached only through permanent
+	// (301 or 308) redirects, and is where it lives now.
+	PermanentURL string
+}
+
+// StatusError is returned when the server answers with a status other than
+// 2xx or 304.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	// RetryAfter is how long the server asked us to wait, if it said.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status: %s", e.Status)
+}
+
+func NewFetcher(opts FetcherOptions) (*Fetcher, error) {
+	if opts.ConnectTimeout <= 0 {
+		opts.ConnectTimeout = defaultConnectTimeout
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = defaultMaxBodySize
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = defaultUserAgent
+	}
+	if opts.HostMinDelay <= 0 {
+		opts.HostMinDelay = defaultHostMinDelay
+	}
+
+	guard, err := newAddressGuard(opts.AllowedHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	plainDialer := &net.Dialer{
+		Timeout:   opts.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	guardedDialer := &net.Dialer{
+		Timeout:   opts.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+		Control:   guard.control,
+	}
+	transport.DialContext = guard.dialContext(guardedDialer, plainDialer)
+	transport.TLSHandshakeTimeout = opts.ConnectTimeout
+	// Compression is negotiated by hand so deflate is accepted as well.
+	transport.DisableCompression = true
+
+	viaProxy := false
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %v", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+		viaProxy = true
+
+		// The proxy was configured on purpose, so it may be internal.
+		guard.allowedHosts[strings.ToLower(proxyURL.Hostname())] = true
+	} else {
+		// So was one in the environment, which the transport falls back to.
+		for _, host := range environmentProxyHosts() {
+			guard.allowedHosts[strings.ToLower(host)] = true
+		}
+	}
+
+	f := &Fetcher{
+		limiter:     newHostLimiter(opts.HostMinDelay, opts.HostBurst),
+		guard:       guard,
+		viaProxy:    viaProxy,
+		userAgent:   opts.UserAgent,
+		maxBodySize: opts.MaxBodySize,
+	}
+	f.client = &http.Client{
+		Transport:     transport,
+		Timeout:       opts.Timeout,
+		CheckRedirect: f.checkRedirect,
+	}
+	f.downloadClient = &http.Client{
+		Transport:     transport,
+		CheckRedirect: f.checkRedirect,
+	}
+
+	return f, nil
+}
+
+// environmentProxyHosts returns the hosts of the proxies set in HTTP_PROXY
+// and HTTPS_PROXY, read the same way net/http reads them.
+func environmentProxyHosts() []string {
+	cfg := httpproxy.FromEnvironment()
+
+	var hosts []string
+	for _, proxy := range []string{cfg.HTTPProxy, cfg.HTTPSProxy} {
+		if proxy == "" {
+			continue
+		}
+
+		// A proxy without a scheme is taken to be http.
+		proxyURL, err := url.Parse(proxy)
+		if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https" && proxyURL.Scheme != "socks5") {
+			proxyURL, err = url.Parse("http://" + proxy)
+			if err != nil {
+				continue
+			}
+		}
+
+		if host := proxyURL.Hostname(); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+func (f *Fetcher) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	return f.checkURL(req.Context(), req.URL)
+}
+
+func (f *Fetcher) checkURL(ctx context.Context, u *url.URL) error {
+	// Proxies from the environment are only known per request.
+	resolve := f.viaProxy
+	if !resolve {
+		proxyURL, err := http.ProxyFromEnvironment(&http.Request{URL: u})
+		resolve = err != nil || proxyURL != nil
+	}
+
+	return f.guard.checkURL(ctx, u, resolve)
+}
+
+// newRequest builds a GET request for rawURL once the address guard has
+// allowed it and the host's rate limit lets it through.
+func (f *Fetcher) newRequest(ctx context.Context, rawURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.checkURL(ctx, req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.limiter.wait(ctx, req.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", f.userAgent)
+	return req, nil
+}
+
+func (f *Fetcher) Fetch(ctx context.Context, feedURL string, cache Cache) (*FetchResult, error) {
+	req, err := f.newRequest(ctx, feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	if cache.ETag != "" {
+		req.Header.Set("If-None-Match", cache.ETag)
+	}
+	if cache.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cache.LastModified)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Servers may leave the validators off a 304, in which case the old
+	// ones are still good.
+	result := &FetchResult{Cache: cache}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		result.Cache.ETag = etag
+	}
+	if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
+		result.Cache.LastModified = lastModified
+	}
+
+	if permanentlyRedirected(resp) {
+		result.PermanentURL = resp.Request.URL.String()
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		result.NotModified = true
+		return result, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		statusErr := &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			statusErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			if statusErr.RetryAfter > 0 {
+				f.limiter.pause(resp.Request.URL.Hostname(), time.Now().Add(statusErr.RetryAfter))
+			}
+		}
+		return nil, statusErr
+	}
+
+	body, err := f.readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Feed, err = parseFeed(body, resp.Header.Get("Content-Type"), resp.Request.URL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// permanentlyRedirected walks back through the redirects that led to resp.
+// Temporary redirects are followed as usual, but a single one in the chain
+// means the final URL can't be treated as the feed's new home.
+func permanentlyRedirected(resp *http.Response) bool {
+	redirected := false
+	for req := resp.Request; req.Response != nil; req = req.Response.Request {
+		redirected = true
+		switch req.Response.StatusCode {
+		case http.StatusMovedPermanently, http.StatusPermanentRedirect:
+		default:
+			return false
+		}
+	}
+
+	return redirected
+}
+
+// readBody decompresses the body if needed and reads at most maxBodySize
+// bytes of it. The limit applies after decompression so a small compressed
+// response can't expand into something that exhausts memory.
+func (f *Fetcher) readBody(resp *http.Response) ([]byte, error) {
+	var reader io.Reader = resp.Body
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading gzip body: %v", err)
+		}
+		defer gz.Close()
+		reader = gz
+	case "deflate":
+		reader = newDeflateReader(resp.Body)
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", resp.Header.Get("Content-Encoding"))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, f.maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(body)) > f.maxBodySize {
+		return nil, fmt.Errorf("feed is larger than %d bytes", f.maxBodySize)
+	}
+
+	return body, nil
+}
+
+// newDeflateReader handles "deflate" bodies, which should be zlib wrapped
+// but are sent as raw deflate data by enough servers that both need to work.
+func newDeflateReader(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	header, err := buffered.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		zr, err := zlib.NewReader(buffered)
+		if err == nil {
+			return zr
+		}
+	}
+
+	return flate.NewReader(buffered)
+}
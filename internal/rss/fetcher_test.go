@@ -0,0 +1,114 @@
+package rss
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestReadBody(t *testing.T) {
+	feed := "<rss><channel><title>Feed</title></channel></rss>"
+	bomb := strings.Repeat("a", 4096)
+
+	cases := []struct {
+		name        string
+		encoding    string
+		body        []byte
+		maxBodySize int64
+		expected    string
+		expectErr   bool
+	}{
+		{name: "plain", body: []byte(feed), maxBodySize: 1024, expected: feed},
+		{name: "plain at the limit", body: []byte(feed), maxBodySize: int64(len(feed)), expected: feed},
+		{name: "plain over the limit", body: []byte(feed), maxBodySize: 10, expectErr: true},
+		{name: "gzip", encoding: "gzip", body: gzipBytes(feed), maxBodySize: 1024, expected: feed},
+		{name: "gzip over the limit once decompressed", encoding: "gzip", body: gzipBytes(bomb), maxBodySize: 1024, expectErr: true},
+		{name: "zlib wrapped deflate", encoding: "deflate", body: zlibBytes(feed), maxBodySize: 1024, expected: feed},
+		{name: "raw deflate", encoding: "Deflate", body: flateBytes(feed), maxBodySize: 1024, expected: feed},
+		{name: "deflate over the limit once decompressed", encoding: "deflate", body: flateBytes(bomb), maxBodySize: 1024, expectErr: true},
+		{name: "unsupported encoding", encoding: "br", body: []byte(feed), maxBodySize: 1024, expectErr: true},
+	}
+
+	for _, c := range cases {
+		f := &Fetcher{maxBodySize: c.maxBodySize}
+		resp := &http.Response{
+			Header: http.Header{},
+			Body:   io.NopCloser(bytes.NewReader(c.body)),
+		}
+		if c.encoding != "" {
+			resp.Header.Set("Content-Encoding", c.encoding)
+		}
+
+		actual, err := f.readBody(resp)
+		if c.expectErr {
+			if err == nil {
+				t.Errorf("%s: Expected an error but got none", c.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Expected no error but got %v", c.name, err)
+			continue
+		}
+		if string(actual) != c.expected {
+			t.Errorf("%s: Expected %q but got %q", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestPermanentlyRedirected(t *testing.T) {
+	cases := []struct {
+		redirects []int
+		expected  bool
+	}{
+		{redirects: nil, expected: false},
+		{redirects: []int{http.StatusMovedPermanently}, expected: true},
+		{redirects: []int{http.StatusPermanentRedirect, http.StatusMovedPermanently}, expected: true},
+		{redirects: []int{http.StatusFound}, expected: false},
+		{redirects: []int{http.StatusMovedPermanently, http.StatusTemporaryRedirect}, expected: false},
+		{redirects: []int{http.StatusTemporaryRedirect, http.StatusMovedPermanently}, expected: false},
+	}
+
+	for _, c := range cases {
+		// Each request made after a redirect points back at the response
+		// that caused it, the way net/http builds the chain.
+		req := &http.Request{}
+		for _, status := range c.redirects {
+			req = &http.Request{Response: &http.Response{StatusCode: status, Request: req}}
+		}
+
+		actual := permanentlyRedirected(&http.Response{StatusCode: http.StatusOK, Request: req})
+		if actual != c.expected {
+			t.Errorf("Expected %v but got %v for %v", c.expected, actual, c.redirects)
+		}
+	}
+}
+
+func gzipBytes(s string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.Bytes()
+}
+
+func zlibBytes(s string) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.Bytes()
+}
+
+func flateBytes(s string) []byte {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.Bytes()
+}
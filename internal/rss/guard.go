@@ -0,0 +1,150 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+// ErrForbiddenAddress is returned for feeds on private, loopback or link-local
+// addresses that haven't been allowed explicitly.
+var ErrForbiddenAddress = errors.New("address is not allowed")
+
+// ErrUnsupportedScheme is returned for URLs that aren't http or https.
+var ErrUnsupportedScheme = errors.New("unsupported url scheme")
+
+// sharedAddressSpace is the carrier-grade NAT range, which netip doesn't
+// count as private but is just as internal.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// addressGuard keeps the fetcher away from internal services. The check runs
+// on the dialed address, after DNS resolution, so it covers every redirect
+// and can't be bypassed by a hostname that resolves to an internal IP.
+type addressGuard struct {
+	allowedHosts    map[string]bool
+	allowedPrefixes []netip.Prefix
+}
+
+// newAddressGuard builds a guard from allowlist entries, each a hostname,
+// an IP address or a CIDR range.
+func newAddressGuard(allowed []string) (*addressGuard, error) {
+	guard := &addressGuard{allowedHosts: make(map[string]bool)}
+
+	for _, entry := range allowed {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			guard.allowedPrefixes = append(guard.allowedPrefixes, prefix.Masked())
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			guard.allowedPrefixes = append(guard.allowedPrefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		if strings.ContainsAny(entry, "/:") {
+			return nil, fmt.Errorf("invalid allowed host: %s", entry)
+		}
+
+		guard.allowedHosts[strings.ToLower(entry)] = true
+	}
+
+	return guard, nil
+}
+
+func (g *addressGuard) hostAllowed(host string) bool {
+	return g.allowedHosts[strings.ToLower(strings.TrimSuffix(host, "."))]
+}
+
+func (g *addressGuard) checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+
+	for _, prefix := range g.allowedPrefixes {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() || addr.IsUnspecified() || sharedAddressSpace.Contains(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+	}
+
+	return nil
+}
+
+// control is a net.Dialer Control function, called with the resolved IP
+// right before connecting.
+func (g *addressGuard) control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+
+	return g.checkAddr(addr)
+}
+
+// dialContext skips the check for allowlisted hostnames and runs every other
+// connection through the guarded dialer.
+func (g *addressGuard) dialContext(guarded, plain *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err == nil && g.hostAllowed(host) {
+			return plain.DialContext(ctx, network, address)
+		}
+
+		return guarded.DialContext(ctx, network, address)
+	}
+}
+
+// checkURL rejects anything that isn't plain http or https, and addresses
+// that are forbidden outright. When a proxy is in use the proxy makes the
+// connection, so the dial time check never sees the feed's own address and
+// the host is resolved here instead.
+func (g *addressGuard) checkURL(ctx context.Context, u *url.URL, resolve bool) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: %q", ErrUnsupportedScheme, u.Scheme)
+	}
+
+	host := u.Hostname()
+	if g.hostAllowed(host) {
+		return nil
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return g.checkAddr(addr)
+	}
+
+	if !resolve {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		err := g.checkAddr(addr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package rss
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestAddressGuard(t *testing.T) {
+	guard, err := newAddressGuard([]string{"10.1.0.0/16", "192.168.1.5", "feeds.internal"})
+	if err != nil {
+		t.Fatalf("Expected allowlist to parse but got %v", err)
+	}
+
+	cases := []struct {
+		input   string
+		allowed bool
+	}{
+		{input: "93.184.216.34", allowed: true},
+		{input: "2606:2800:220:1:248:1893:25c8:1946", allowed: true},
+		{input: "127.0.0.1", allowed: false},
+		{input: "::1", allowed: false},
+		{input: "::ffff:127.0.0.1", allowed: false},
+		{input: "10.0.0.1", allowed: false},
+		{input: "172.16.0.1", allowed: false},
+		{input: "192.168.0.1", allowed: false},
+		{input: "169.254.169.254", allowed: false},
+		{input: "fe80::1", allowed: false},
+		{input: "fd00::1", allowed: false},
+		{input: "100.64.0.1", allowed: false},
+		{input: "0.0.0.0", allowed: false},
+		{input: "10.1.2.3", allowed: true},
+		{input: "192.168.1.5", allowed: true},
+	}
+
+	for _, c := range cases {
+		err := guard.checkAddr(netip.MustParseAddr(c.input))
+		if c.allowed && err != nil {
+			t.Errorf("Expected %s to be allowed but got %v", c.input, err)
+		}
+		if !c.allowed && !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Expected %s to be forbidden but got %v", c.input, err)
+		}
+	}
+
+	if !guard.hostAllowed("Feeds.Internal.") {
+		t.Errorf("Expected feeds.internal to be allowed")
+	}
+}
+
+func TestEnvironmentProxyAllowed(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "10.1.2.3:3128")
+	t.Setenv("HTTPS_PROXY", "http://Proxy.Corp.Example:8080")
+	t.Setenv("NO_PROXY", "")
+
+	f, err := NewFetcher(FetcherOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	cases := []struct {
+		input   string
+		allowed bool
+	}{
+		{input: "10.1.2.3", allowed: true},
+		{input: "proxy.corp.example", allowed: true},
+		{input: "10.1.2.4", allowed: false},
+	}
+
+	for _, c := range cases {
+		if f.guard.hostAllowed(c.input) != c.allowed {
+			t.Errorf("Expected %s allowed to be %v but got %v", c.input, c.allowed, !c.allowed)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPauseWait is the longest a fetch will sit and wait for a host that
+// asked us to back off. Anything longer fails with a HostPausedError so the
+// feed can be rescheduled instead of holding up a worker.
+const maxPauseWait = 30 * time.Second
+
+// HostPausedError is returned without making a request when the feed's host
+// has asked, through Retry-After, not to be contacted until later.
+type HostPausedError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *HostPausedError) Error() string {
+	return fmt.Sprintf("%s asked not to be fetched until %s", e.Host, e.Until.Format(time.RFC1123))
+}
+
+// hostLimiter is a token bucket per host, so feeds that share a host are
+// spaced out by at least interval once the burst is used up, however many
+// workers are fetching.
+type hostLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	hosts    map[string]*hostBucket
+}
+
+type hostBucket struct {
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newHostLimiter(interval time.Duration, burst int) *hostLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &hostLimiter{
+		interval: interval,
+		burst:    float64(burst),
+		hosts:    make(map[string]*hostBucket),
+	}
+}
+
+// wait blocks until a request to host is allowed.
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	delay, err := l.reserve(strings.ToLower(host), time.Now())
+	if err != nil {
+		return err
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token for host and returns how long to wait before it can
+// be used. Tokens may go negative, which queues concurrent callers one
+// interval apart.
+func (l *hostLimiter) reserve(host string, now time.Time) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.hosts[host]
+	if !ok {
+		bucket = &hostBucket{tokens: l.burst, last: now}
+		l.hosts[host] = bucket
+	}
+
+	var delay time.Duration
+	if bucket.pausedUntil.After(now) {
+		delay = bucket.pausedUntil.Sub(now)
+		if delay > maxPauseWait {
+			return 0, &HostPausedError{Host: host, Until: bucket.pausedUntil}
+		}
+	}
+
+	if l.interval <= 0 {
+		return delay, nil
+	}
+
+	bucket.tokens += float64(now.Sub(bucket.last)) / float64(l.interval)
+	if bucket.tokens > l.burst {
+		bucket.tokens = l.burst
+	}
+	bucket.last = now
+
+	bucket.tokens--
+	if bucket.tokens < 0 {
+		if wait := time.Duration(-bucket.tokens * float64(l.interval)); wait > delay {
+			delay = wait
+		}
+	}
+
+	return delay, nil
+}
+
+// pause stops requests to host until the given time.
+func (l *hostLimiter) pause(host string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	host = strings.ToLower(host)
+	bucket, ok := l.hosts[host]
+	if !ok {
+		bucket = &hostBucket{tokens: l.burst, last: time.Now()}
+		l.hosts[host] = bucket
+	}
+
+	if until.After(bucket.pausedUntil) {
+		bucket.pausedUntil = until
+	}
+}
+
+// parseRetryAfter reads a Retry-After header, which is either a number of
+// seconds or an HTTP date. It returns zero if the header is missing or bad.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,91 @@
+package rss
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, time.October, 21, 7, 27, 0, 0, time.UTC)
+
+	cases := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{input: "120", expected: 2 * time.Minute},
+		{input: " 30 ", expected: 30 * time.Second},
+		{input: "0", expected: 0},
+		{input: "-5", expected: 0},
+		{input: "Wed, 21 Oct 2026 07:28:00 GMT", expected: time.Minute},
+		{input: "Wednesday, 21-Oct-26 07:29:00 GMT", expected: 2 * time.Minute},
+		{input: "Wed, 21 Oct 2026 07:00:00 GMT", expected: 0},
+		{input: "in a bit", expected: 0},
+		{input: "", expected: 0},
+	}
+
+	for _, c := range cases {
+		actual := parseRetryAfter(c.input, now)
+		if actual != c.expected {
+			t.Errorf("Expected %v but got %v for %q", c.expected, actual, c.input)
+		}
+	}
+}
+
+func TestHostLimiterReserve(t *testing.T) {
+	limiter := newHostLimiter(time.Second, 2)
+	now := time.Now()
+
+	// The burst goes out at once, then requests queue up one interval apart
+	// and the bucket refills while the host is left alone.
+	cases := []struct {
+		host     string
+		offset   time.Duration
+		expected time.Duration
+	}{
+		{host: "example.com", offset: 0, expected: 0},
+		{host: "example.com", offset: 0, expected: 0},
+		{host: "example.com", offset: 0, expected: time.Second},
+		{host: "example.com", offset: 0, expected: 2 * time.Second},
+		{host: "example.org", offset: 0, expected: 0},
+		{host: "example.com", offset: 3 * time.Second, expected: 0},
+		{host: "example.com", offset: 3 * time.Second, expected: time.Second},
+	}
+
+	for i, c := range cases {
+		actual, err := limiter.reserve(c.host, now.Add(c.offset))
+		if err != nil {
+			t.Errorf("Expected no error but got %v", err)
+			continue
+		}
+		if actual != c.expected {
+			t.Errorf("Expected %v but got %v for request %d to %s", c.expected, actual, i, c.host)
+		}
+	}
+}
+
+func TestHostLimiterPause(t *testing.T) {
+	limiter := newHostLimiter(0, 1)
+	now := time.Now()
+
+	limiter.pause("Short.Example", now.Add(10*time.Second))
+	delay, err := limiter.reserve("short.example", now)
+	if err != nil {
+		t.Errorf("Expected no error but got %v", err)
+	}
+	if delay != 10*time.Second {
+		t.Errorf("Expected %v but got %v", 10*time.Second, delay)
+	}
+
+	until := now.Add(maxPauseWait + time.Minute)
+	limiter.pause("long.example", until)
+	_, err = limiter.reserve("long.example", now)
+
+	var pausedErr *HostPausedError
+	if !errors.As(err, &pausedErr) {
+		t.Fatalf("Expected a HostPausedError but got %v", err)
+	}
+	if !pausedErr.Until.Equal(until) {
+		t.Errorf("Expected %v but got %v", until, pausedErr.Until)
+	}
+}
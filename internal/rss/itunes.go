@@ -0,0 +1,111 @@
+package rss
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PodcastEpisode is the podcast side of an item: the media file to play and
+// what the itunes: namespace says about it.
+type PodcastEpisode struct {
+	Media Enclosure
+	// Duration is zero when the feed doesn't give one.
+	Duration time.Duration
+	Episode  int
+	Season   int
+	Image    string
+	Explicit bool
+}
+
+type itunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+// podcastEpisode builds the episode for an RSS item, or returns nil if the
+// item has no audio or video to go with it. Episodes without their own
+// image use the show's.
+func (item RSSItem) podcastEpisode(showImage string) *PodcastEpisode {
+	media, ok := mediaEnclosure(item.Enclosures)
+	if !ok {
+		return nil
+	}
+
+	length, _ := strconv.ParseInt(strings.TrimSpace(media.Length), 10, 64)
+	episode := &PodcastEpisode{
+		Media: Enclosure{
+			URL:    strings.TrimSpace(media.URL),
+			Type:   strings.TrimSpace(media.Type),
+			Length: length,
+		},
+		Duration: parseItunesDuration(item.ItunesDuration),
+		Image:    strings.TrimSpace(item.ItunesImage.Href),
+		Explicit: parseItunesExplicit(item.ItunesExplicit),
+	}
+	episode.Episode, _ = strconv.Atoi(strings.TrimSpace(item.ItunesEpisode))
+	episode.Season, _ = strconv.Atoi(strings.TrimSpace(item.ItunesSeason))
+	if episode.Image == "" {
+		episode.Image = showImage
+	}
+
+	return episode
+}
+
+// mediaEnclosure picks the first audio or video enclosure. Podcasts don't
+// always set a type, so an untyped enclosure is taken if there is no other.
+func mediaEnclosure(enclosures []RSSEnclosure) (RSSEnclosure, bool) {
+	var untyped *RSSEnclosure
+	for i, enclosure := range enclosures {
+		if strings.TrimSpace(enclosure.URL) == "" {
+			continue
+		}
+
+		mediaType := strings.ToLower(strings.TrimSpace(enclosure.Type))
+		if strings.HasPrefix(mediaType, "audio/") || strings.HasPrefix(mediaType, "video/") {
+			return enclosure, true
+		}
+		if mediaType == "" && untyped == nil {
+			untyped = &enclosures[i]
+		}
+	}
+
+	if untyped != nil {
+		return *untyped, true
+	}
+
+	return RSSEnclosure{}, false
+}
+
+// parseItunesDuration reads an itunes:duration, which is either a number
+// of seconds or H:MM:SS / MM:SS. Anything else comes back as zero.
+func parseItunesDuration(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0
+	}
+
+	var seconds float64
+	for _, part := range parts {
+		n, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || n < 0 {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second)
+}
+
+func parseItunesExplicit(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "true", "explicit":
+		return true
+	default:
+		return false
+	}
+}
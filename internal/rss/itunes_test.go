@@ -0,0 +1,75 @@
+package rss
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseItunesDuration(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{input: "3723", expected: time.Hour + 2*time.Minute + 3*time.Second},
+		{input: "1:02:03", expected: time.Hour + 2*time.Minute + 3*time.Second},
+		{input: "62:03", expected: time.Hour + 2*time.Minute + 3*time.Second},
+		{input: " 45:00 ", expected: 45 * time.Minute},
+		{input: "90.6", expected: 91 * time.Second},
+		{input: "", expected: 0},
+		{input: "about an hour", expected: 0},
+		{input: "1:2:3:4", expected: 0},
+	}
+
+	for _, c := range cases {
+		actual := parseItunesDuration(c.input)
+		if actual != c.expected {
+			t.Errorf("Expected %v but got %v for %q", c.expected, actual, c.input)
+		}
+	}
+}
+
+func TestPodcastEpisode(t *testing.T) {
+	body := []byte(`<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+<channel>
+	<title>Show</title>
+	<itunes:image href="https://example.com/show.jpg"/>
+	<item>
+		<title>Episode 5</title>
+		<enclosure url="https://example.com/cover.jpg" type="image/jpeg" length="10"/>
+		<enclosure url="https://example.com/5.mp3" type="audio/mpeg" length="12345"/>
+		<itunes:duration>1:02:03</itunes:duration>
+		<itunes:episode>5</itunes:episode>
+		<itunes:season>2</itunes:season>
+		<itunes:explicit>true</itunes:explicit>
+	</item>
+	<item>
+		<title>Blog post</title>
+	</item>
+</channel>
+</rss>`)
+
+	feed, err := parseFeed(body, "application/rss+xml", "")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	if len(feed.Items) != 2 {
+		t.Fatalf("Expected 2 items but got %d", len(feed.Items))
+	}
+
+	expected := PodcastEpisode{
+		Media:    Enclosure{URL: "https://example.com/5.mp3", Type: "audio/mpeg", Length: 12345},
+		Duration: time.Hour + 2*time.Minute + 3*time.Second,
+		Episode:  5,
+		Season:   2,
+		Image:    "https://example.com/show.jpg",
+		Explicit: true,
+	}
+	if feed.Items[0].Podcast == nil || *feed.Items[0].Podcast != expected {
+		t.Errorf("Expected %+v but got %+v", expected, feed.Items[0].Podcast)
+	}
+
+	if feed.Items[1].Podcast != nil {
+		t.Errorf("Expected no episode but got %+v", feed.Items[1].Podcast)
+	}
+}
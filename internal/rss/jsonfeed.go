@@ -0,0 +1,158 @@
+package rss
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const jsonFeedVersionPrefix = "https://jsonfeed.org/version/1"
+
+// JSONFeed is a JSON Feed document, versions 1.0 and 1.1 share this layout.
+type JSONFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description"`
+	Icon        string         `json:"icon"`
+	Favicon     string         `json:"favicon"`
+	Language    string         `json:"language"`
+	Items       []JSONFeedItem `json:"items"`
+}
+
+type JSONFeedItem struct {
+	ID            jsonFeedID           `json:"id"`
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html"`
+	ContentText   string               `json:"content_text"`
+	Summary       string               `json:"summary"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Author        *JSONFeedAuthor      `json:"author"`
+	Authors       []JSONFeedAuthor     `json:"authors"`
+	Tags          []string             `json:"tags"`
+	Attachments   []JSONFeedAttachment `json:"attachments"`
+}
+
+// JSONFeedAuthor is used by both the single author of version 1.0 and the
+// authors list that replaced it in 1.1.
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type JSONFeedAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes"`
+}
+
+// jsonFeedID is a string per the spec, but plenty of publishers emit
+// numbers, so accept both.
+type jsonFeedID string
+
+func (id *jsonFeedID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = jsonFeedID(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid item id: %s", data)
+	}
+
+	*id = jsonFeedID(n.String())
+	return nil
+}
+
+func parseJSONFeed(body []byte) (*Feed, error) {
+	var feed JSONFeed
+	err := json.Unmarshal(body, &feed)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(feed.Version, jsonFeedVersionPrefix) {
+		return nil, fmt.Errorf("unsupported JSON Feed version: %q", feed.Version)
+	}
+
+	return feed.normalize(), nil
+}
+
+func (f *JSONFeed) normalize() *Feed {
+	feed := &Feed{
+		Title:       f.Title,
+		Link:        f.HomePageURL,
+		Description: f.Description,
+		Image:       f.Icon,
+		Language:    f.Language,
+	}
+	if feed.Image == "" {
+		feed.Image = f.Favicon
+	}
+
+	for _, item := range f.Items {
+		link := item.URL
+		if link == "" {
+			link = item.ExternalURL
+		}
+
+		description := item.ContentHTML
+		if description == "" {
+			description = item.ContentText
+		}
+		if description == "" {
+			description = item.Summary
+		}
+
+		content := item.ContentHTML
+		if content == "" {
+			content = item.ContentText
+		}
+
+		authors := item.Authors
+		if len(authors) == 0 && item.Author != nil {
+			authors = []JSONFeedAuthor{*item.Author}
+		}
+
+		var names []string
+		for _, author := range authors {
+			if author.Name != "" {
+				names = append(names, author.Name)
+			}
+		}
+
+		var enclosures []Enclosure
+		for _, attachment := range item.Attachments {
+			if attachment.URL == "" {
+				continue
+			}
+
+			enclosures = append(enclosures, Enclosure{
+				URL:    attachment.URL,
+				Type:   attachment.MimeType,
+				Length: attachment.SizeInBytes,
+			})
+		}
+
+		feed.Items = append(feed.Items, Item{
+			ID:          string(item.ID),
+			Title:       item.Title,
+			Link:        link,
+			Description: description,
+			Content:     content,
+			Author:      strings.Join(names, ", "),
+			Categories:  item.Tags,
+			Enclosures:  enclosures,
+			PubDate:     item.DatePublished,
+			Updated:     item.DateModified,
+		})
+	}
+
+	return feed
+}
@@ -0,0 +1,165 @@
+package rss
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONFeed(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		expected Feed
+	}{
+		{
+			name: "minimal",
+			body: `{
+	"version": "https://jsonfeed.org/version/1.1",
+	"title": "Example",
+	"home_page_url": "https://example.com/",
+	"items": [
+		{"id": "1", "url": "https://example.com/1", "content_text": "Hello", "date_published": "2024-05-01T10:00:00Z"}
+	]
+}`,
+			expected: Feed{
+				Title: "Example",
+				Link:  "https://example.com/",
+				Items: []Item{
+					{
+						ID:          "1",
+						Link:        "https://example.com/1",
+						Description: "Hello",
+						Content:     "Hello",
+						PubDate:     "2024-05-01T10:00:00Z",
+					},
+				},
+			},
+		},
+		{
+			name: "version 1.0 author and numeric id",
+			body: `{
+	"version": "https://jsonfeed.org/version/1",
+	"title": "Old",
+	"favicon": "https://example.com/favicon.ico",
+	"items": [
+		{
+			"id": 42,
+			"external_url": "https://elsewhere.example.org/post",
+			"title": "Linked",
+			"summary": "A link",
+			"author": {"name": "Tom"},
+			"tags": ["links"],
+			"attachments": [
+				{"url": "https://example.com/42.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 1024},
+				{"mime_type": "audio/mpeg"}
+			]
+		}
+	]
+}`,
+			expected: Feed{
+				Title: "Old",
+				Image: "https://example.com/favicon.ico",
+				Items: []Item{
+					{
+						ID:          "42",
+						Title:       "Linked",
+						Link:        "https://elsewhere.example.org/post",
+						Description: "A link",
+						Author:      "Tom",
+						Categories:  []string{"links"},
+						Enclosures:  []Enclosure{{URL: "https://example.com/42.mp3", Type: "audio/mpeg", Length: 1024}},
+					},
+				},
+			},
+		},
+		{
+			name: "version 1.1 authors win over author",
+			body: `{
+	"version": "https://jsonfeed.org/version/1.1",
+	"title": "New",
+	"icon": "https://example.com/icon.png",
+	"favicon": "https://example.com/favicon.ico",
+	"language": "en",
+	"items": [
+		{
+			"id": "7",
+			"content_html": "<p>Hi</p>",
+			"content_text": "Hi",
+			"author": {"name": "Old Tom"},
+			"authors": [{"name": "Tom"}, {"url": "https://example.com/nameless"}, {"name": "Jerry"}]
+		}
+	]
+}`,
+			expected: Feed{
+				Title:    "New",
+				Image:    "https://example.com/icon.png",
+				Language: "en",
+				Items: []Item{
+					{
+						ID:          "7",
+						Description: "<p>Hi</p>",
+						Content:     "<p>Hi</p>",
+						Author:      "Tom, Jerry",
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := parseJSONFeed([]byte(c.body))
+		if err != nil {
+			t.Errorf("%s: Expected no error but got %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(*actual, c.expected) {
+			t.Errorf("%s: Expected %+v but got %+v", c.name, c.expected, *actual)
+		}
+	}
+}
+
+func TestParseJSONFeedErrors(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    `{"version": "https://example.com/version/2", "items": []}`,
+			expected: `unsupported JSON Feed version: "https://example.com/version/2"`,
+		},
+		{
+			input:    `{"version": "https://jsonfeed.org/version/1.1", "items": [{"id": true}]}`,
+			expected: "invalid item id: true",
+		},
+	}
+
+	for _, c := range cases {
+		_, err := parseJSONFeed([]byte(c.input))
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("Expected %v but got %v", c.expected, err)
+		}
+	}
+}
+
+func TestIsJSON(t *testing.T) {
+	cases := []struct {
+		body        string
+		contentType string
+		expected    bool
+	}{
+		{body: `{"version": "https://jsonfeed.org/version/1.1"}`, expected: true},
+		{body: "\ufeff\n  {\"version\": \"https://jsonfeed.org/version/1\"}", expected: true},
+		{body: "", contentType: "application/feed+json", expected: true},
+		{body: "", contentType: "application/json; charset=utf-8", expected: true},
+		{body: `<rss version="2.0"></rss>`, contentType: "application/rss+xml", expected: false},
+		{body: `<?xml version="1.0"?><feed></feed>`, expected: false},
+		{body: "", expected: false},
+	}
+
+	for _, c := range cases {
+		actual := isJSON([]byte(c.body), c.contentType)
+		if actual != c.expected {
+			t.Errorf("Expected %v but got %v for %q (%s)", c.expected, actual, c.body, c.contentType)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+)
+
+// ParseMode records which parser managed to read a feed.
+type ParseMode string
+
+const (
+	ParseStrict  ParseMode = "strict"
+	ParseLenient ParseMode = "lenient"
+)
+
+// htmlVoidTags are the HTML elements that never have content, so are closed
+// as soon as they open. xml.HTMLAutoClose also has link, which would close
+// every RSS <link> before its URL and let the stray </link> end the channel.
+var htmlVoidTags = []string{
+	"area",
+	"basefont",
+	"br",
+	"col",
+	"frame",
+	"hr",
+	"img",
+	"input",
+	"isindex",
+	"param",
+}
+
+// newLenientDecoder returns a decoder that forgives the mistakes real feeds
+// make most: bare ampersands, HTML entities like &nbsp; that XML doesn't
+// define, unclosed HTML tags such as <br>, control characters and junk
+// before the XML declaration.
+func newLenientDecoder(body []byte) *xml.Decoder {
+	decoder := newXMLDecoder(cleanXML(body))
+	decoder.Strict = false
+	decoder.AutoClose = htmlVoidTags
+	decoder.Entity = xml.HTMLEntity
+
+	return decoder
+}
+
+// cleanXML drops whatever comes before the XML declaration, or before the
+// first tag if there is none, and strips out characters XML doesn't allow.
+// Stray output like a PHP warning or a blank line ahead of the declaration
+// is enough to make the strict parser give up.
+func cleanXML(body []byte) []byte {
+	if start := bytes.Index(body, []byte("<?xml")); start > 0 {
+		body = body[start:]
+	} else if start < 0 {
+		if start := bytes.IndexByte(body, '<'); start > 0 {
+			body = body[start:]
+		}
+	}
+
+	body = bytes.ToValidUTF8(body, []byte("\uFFFD"))
+
+	return bytes.Map(func(r rune) rune {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			return -1
+		}
+		if r == 0xFFFE || r == 0xFFFF {
+			return -1
+		}
+		return r
+	}, body)
+}
@@ -0,0 +1,120 @@
+package rss
+
+import "testing"
+
+func TestParseFeedLenient(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		mode     ParseMode
+		expected string
+	}{
+		{
+			name:     "well-formed",
+			body:     `<rss><channel><item><title>Tom &amp; Jerry</title></item></channel></rss>`,
+			mode:     ParseStrict,
+			expected: "Tom & Jerry",
+		},
+		{
+			name:     "bare ampersand",
+			body:     `<rss><channel><item><title>Tom & Jerry</title></item></channel></rss>`,
+			mode:     ParseLenient,
+			expected: "Tom & Jerry",
+		},
+		{
+			name:     "HTML entity",
+			body:     `<rss><channel><item><title>Tom&nbsp;&amp;&nbsp;Jerry&hellip;</title></item></channel></rss>`,
+			mode:     ParseLenient,
+			expected: "Tom\u00a0&\u00a0Jerry…",
+		},
+		{
+			name:     "junk before the prolog",
+			body:     "<br />\n<b>Warning</b>: something went wrong<br />\n<?xml version=\"1.0\"?><rss><channel><item><title>Tom</title></item></channel></rss>",
+			mode:     ParseLenient,
+			expected: "Tom",
+		},
+		{
+			name:     "control characters",
+			body:     "<rss><channel><item><title>To\x0bm\x1b</title></item></channel></rss>",
+			mode:     ParseLenient,
+			expected: "Tom",
+		},
+		{
+			name:     "unclosed HTML tag",
+			body:     `<rss><channel><item><title>Tom</title><description>one<br>two</description></item></channel></rss>`,
+			mode:     ParseLenient,
+			expected: "Tom",
+		},
+	}
+
+	for _, c := range cases {
+		feed, err := parseFeed([]byte(c.body), "", "")
+		if err != nil {
+			t.Errorf("%s: Expected no error but got %v", c.name, err)
+			continue
+		}
+
+		if feed.ParseMode != c.mode {
+			t.Errorf("%s: Expected %v but got %v", c.name, c.mode, feed.ParseMode)
+		}
+
+		if len(feed.Items) != 1 || feed.Items[0].Title != c.expected {
+			t.Errorf("%s: Expected %q but got %+v", c.name, c.expected, feed.Items)
+		}
+	}
+}
+
+func TestParseFeedLenientFailure(t *testing.T) {
+	_, err := parseFeed([]byte(`not a feed at all`), "", "")
+	if err == nil {
+		t.Errorf("Expected an error but got none")
+	}
+}
+
+func TestParseFeedLenientLinks(t *testing.T) {
+	body := []byte(`<rss version="2.0"><channel>
+	<title>Tom&nbsp;&amp;&nbsp;Jerry</title>
+	<link>https://example.com/</link>
+	<item>
+		<title>One</title>
+		<link>https://example.com/1</link>
+		<description>First&nbsp;post</description>
+	</item>
+	<item>
+		<title>Two</title>
+		<link>https://example.com/2</link>
+		<description>Second post</description>
+	</item>
+</channel></rss>`)
+
+	feed, err := parseFeed(body, "", "")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	if feed.ParseMode != ParseLenient {
+		t.Errorf("Expected %v but got %v", ParseLenient, feed.ParseMode)
+	}
+	if feed.Link != "https://example.com/" {
+		t.Errorf("Expected %q but got %q", "https://example.com/", feed.Link)
+	}
+
+	cases := []struct {
+		link        string
+		description string
+	}{
+		{link: "https://example.com/1", description: "First\u00a0post"},
+		{link: "https://example.com/2", description: "Second post"},
+	}
+
+	if len(feed.Items) != len(cases) {
+		t.Fatalf("Expected %d items but got %d", len(cases), len(feed.Items))
+	}
+
+	for i, c := range cases {
+		item := feed.Items[i]
+		if item.Link != c.link || item.Description != c.description {
+			t.Errorf("Expected %q and %q but got %q and %q", c.link, c.description, item.Link, item.Description)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package rss
+
+import (
+	"net/url"
+	"strings"
+)
+
+// resolveLinks makes every URL in the feed absolute. Relative URLs are
+// resolved against the xml:base in effect where the feed sets one, and
+// otherwise against the channel link, which is itself resolved against the
+// URL the feed was fetched from. Each item's Base ends up as the URL that
+// relative links in its description and content are relative to.
+func (f *Feed) resolveLinks(feedURL string) {
+	base, err := url.Parse(feedURL)
+	if err != nil || !base.IsAbs() {
+		base = nil
+	}
+	base = resolveBase(base, f.base)
+
+	f.Link = resolveURL(base, f.Link)
+	f.Image = resolveURL(base, f.Image)
+
+	// Item links usually point into the site rather than at the feed, so
+	// the channel link is the better base unless xml:base says otherwise.
+	itemsBase := base
+	if f.base == "" && f.Link != "" {
+		itemsBase = resolveBase(itemsBase, f.Link)
+	}
+
+	for i := range f.Items {
+		item := &f.Items[i]
+		itemBase := resolveBase(itemsBase, item.Base)
+
+		// Posts without a guid are keyed on their link, which has to stay
+		// the same whether or not it was relative.
+		item.link = item.Link
+		item.Link = resolveURL(itemBase, item.Link)
+		item.Comments = resolveURL(itemBase, item.Comments)
+		for j := range item.Enclosures {
+			item.Enclosures[j].URL = resolveURL(itemBase, item.Enclosures[j].URL)
+		}
+		if item.Podcast != nil {
+			item.Podcast.Media.URL = resolveURL(itemBase, item.Podcast.Media.URL)
+			item.Podcast.Image = resolveURL(itemBase, item.Podcast.Image)
+		}
+
+		switch {
+		case item.Base != "" && itemBase != nil:
+			item.Base = itemBase.String()
+		case item.Link != "":
+			item.Base = item.Link
+		case itemBase != nil:
+			item.Base = itemBase.String()
+		default:
+			item.Base = ""
+		}
+	}
+}
+
+// resolveBase applies a base URL, such as an xml:base attribute, on top of
+// the one already in effect. An empty or broken ref leaves base as it is.
+func resolveBase(base *url.URL, ref string) *url.URL {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return base
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return base
+	}
+
+	if base == nil {
+		if !u.IsAbs() {
+			return nil
+		}
+		return u
+	}
+
+	return base.ResolveReference(u)
+}
+
+// resolveURL resolves ref against base. Without a base, or if ref can't be
+// parsed, it is returned unchanged.
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || base == nil {
+		return ref
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return base.ResolveReference(u).String()
+}
+
+// joinBase combines nested xml:base attributes, which may each be relative
+// to the one outside them, before the feed's URL is known.
+func joinBase(outer, inner string) string {
+	outer, inner = strings.TrimSpace(outer), strings.TrimSpace(inner)
+	if outer == "" {
+		return inner
+	}
+	if inner == "" {
+		return outer
+	}
+
+	outerURL, err := url.Parse(outer)
+	if err != nil {
+		return inner
+	}
+	innerURL, err := url.Parse(inner)
+	if err != nil {
+		return outer
+	}
+
+	return outerURL.ResolveReference(innerURL).String()
+}
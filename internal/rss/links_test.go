@@ -0,0 +1,126 @@
+package rss
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFeedResolvesLinks(t *testing.T) {
+	type expectedItem struct {
+		key       string
+		link      string
+		comments  string
+		enclosure string
+		base      string
+	}
+
+	cases := []struct {
+		fixture string
+		link    string
+		image   string
+		items   []expectedItem
+	}{
+		{
+			fixture: "relative.rss",
+			link:    "https://example.com/blog/",
+			image:   "https://example.com/logo.png",
+			items: []expectedItem{
+				{
+					key:       "posts/123",
+					link:      "https://example.com/blog/posts/123",
+					comments:  "https://example.com/blog/posts/123#comments",
+					enclosure: "https://example.com/media/123.mp3",
+					base:      "https://example.com/blog/posts/123",
+				},
+				{
+					key:  "old/7",
+					link: "https://cdn.example.net/archive/old/7",
+					base: "https://cdn.example.net/archive/",
+				},
+				{
+					key:  "https://elsewhere.example.org/post",
+					link: "https://elsewhere.example.org/post",
+					base: "https://elsewhere.example.org/post",
+				},
+			},
+		},
+		{
+			fixture: "relative.atom",
+			link:    "https://example.com/journal/",
+			image:   "https://example.com/journal/logo.svg",
+			items: []expectedItem{
+				{
+					key:      "urn:example:journal:1",
+					link:     "https://example.com/journal/2024/first",
+					comments: "https://example.com/journal/2024/first/comments",
+					base:     "https://example.com/journal/2024/first",
+				},
+				{
+					key:       "urn:example:journal:2",
+					link:      "https://example.com/elsewhere/second",
+					enclosure: "https://example.com/elsewhere/second.ogg",
+					base:      "https://example.com/elsewhere/",
+				},
+			},
+		},
+		{
+			fixture: "relative.json",
+			link:    "https://example.com/",
+			image:   "https://example.com/icon.png",
+			items: []expectedItem{
+				{
+					key:       "1",
+					link:      "https://example.com/notes/1",
+					enclosure: "https://example.com/files/1.pdf",
+					base:      "https://example.com/notes/1",
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		body, err := os.ReadFile(filepath.Join("testdata", c.fixture))
+		if err != nil {
+			t.Fatalf("Expected no error but got %v", err)
+		}
+
+		feed, err := parseFeed(body, "", "https://example.com/feeds/main.xml")
+		if err != nil {
+			t.Errorf("%s: Expected no error but got %v", c.fixture, err)
+			continue
+		}
+
+		if feed.Link != c.link {
+			t.Errorf("%s: Expected link %q but got %q", c.fixture, c.link, feed.Link)
+		}
+		if feed.Image != c.image {
+			t.Errorf("%s: Expected image %q but got %q", c.fixture, c.image, feed.Image)
+		}
+
+		if len(feed.Items) != len(c.items) {
+			t.Errorf("%s: Expected %d items but got %d", c.fixture, len(c.items), len(feed.Items))
+			continue
+		}
+
+		for i, expected := range c.items {
+			item := feed.Items[i]
+
+			enclosure := ""
+			if len(item.Enclosures) > 0 {
+				enclosure = item.Enclosures[0].URL
+			}
+
+			actual := expectedItem{
+				key:       item.Key(),
+				link:      item.Link,
+				comments:  item.Comments,
+				enclosure: enclosure,
+				base:      item.Base,
+			}
+			if actual != expected {
+				t.Errorf("%s item %d: Expected %+v but got %+v", c.fixture, i, expected, actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package rss
+
+import (
+	"encoding/xml"
+	"html"
+	"strings"
+)
+
+const (
+	rdfNamespace   = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	rss10Namespace = "http://purl.org/rss/1.0/"
+)
+
+// RDFFeed is an RSS 1.0 document. Unlike RSS 2.0 the items are siblings of
+// the channel under the rdf:RDF root rather than children of it.
+type RDFFeed struct {
+	XMLName xml.Name `xml:"RDF"`
+	Base    string   `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Channel struct {
+		Title       rdfText `xml:"title"`
+		Link        rdfText `xml:"link"`
+		Description rdfText `xml:"description"`
+		Language    string  `xml:"http://purl.org/dc/elements/1.1/ language"`
+		Image       struct {
+			Resource string `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# resource,attr"`
+		} `xml:"image"`
+	} `xml:"channel"`
+	Image struct {
+		URL rdfText `xml:"url"`
+	} `xml:"image"`
+	Items []RDFItem `xml:"item"`
+}
+
+type RDFItem struct {
+	About          string   `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Title          rdfText  `xml:"title"`
+	Link           rdfText  `xml:"link"`
+	Description    rdfText  `xml:"description"`
+	ContentEncoded string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Creator        string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Subjects       []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+	Date           string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+// rdfText is the text of an RSS 1.0 element. Like rssText it keeps elements
+// from other namespaces, such as <media:title>, out of the RSS ones.
+type rdfText string
+
+func (t *rdfText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if start.Name.Space != rss10Namespace && start.Name.Space != "" {
+		return d.Skip()
+	}
+
+	var s string
+	err := d.DecodeElement(&s, &start)
+	if err != nil {
+		return err
+	}
+
+	*t = rdfText(s)
+	return nil
+}
+
+func parseRDF(decoder *xml.Decoder) (*Feed, error) {
+	var feed RDFFeed
+	err := decoder.Decode(&feed)
+	if err != nil {
+		return nil, err
+	}
+
+	return feed.normalize(), nil
+}
+
+func (f *RDFFeed) normalize() *Feed {
+	feed := &Feed{
+		Title:       html.UnescapeString(string(f.Channel.Title)),
+		Link:        strings.TrimSpace(string(f.Channel.Link)),
+		Description: html.UnescapeString(string(f.Channel.Description)),
+		Image:       strings.TrimSpace(string(f.Image.URL)),
+		Language:    strings.TrimSpace(f.Channel.Language),
+		base:        strings.TrimSpace(f.Base),
+	}
+	if feed.Image == "" {
+		feed.Image = strings.TrimSpace(f.Channel.Image.Resource)
+	}
+
+	for _, item := range f.Items {
+		var categories []string
+		for _, subject := range item.Subjects {
+			if strings.TrimSpace(subject) != "" {
+				categories = append(categories, strings.TrimSpace(subject))
+			}
+		}
+
+		feed.Items = append(feed.Items, Item{
+			ID:          item.About,
+			Title:       html.UnescapeString(string(item.Title)),
+			Link:        strings.TrimSpace(string(item.Link)),
+			Description: strings.TrimSpace(string(item.Description)),
+			Content:     strings.TrimSpace(item.ContentEncoded),
+			Author:      strings.TrimSpace(item.Creator),
+			Categories:  categories,
+			DCDate:      item.Date,
+		})
+	}
+
+	return feed
+}
@@ -0,0 +1,158 @@
+package rss
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRDF(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		expected Feed
+	}{
+		{
+			name: "minimal",
+			body: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+	<channel rdf:about="https://example.com/">
+		<title>Example</title>
+		<link>https://example.com/</link>
+		<description>An RSS 1.0 feed</description>
+	</channel>
+	<item rdf:about="https://example.com/1">
+		<title>First</title>
+		<link>https://example.com/1</link>
+	</item>
+</rdf:RDF>`,
+			expected: Feed{
+				Title:       "Example",
+				Link:        "https://example.com/",
+				Description: "An RSS 1.0 feed",
+				Items: []Item{
+					{ID: "https://example.com/1", Title: "First", Link: "https://example.com/1"},
+				},
+			},
+		},
+		{
+			name: "dublin core and a channel image reference",
+			body: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+	<channel rdf:about="https://example.com/">
+		<title>Tom &amp;amp; Jerry</title>
+		<link>https://example.com/</link>
+		<dc:language>en</dc:language>
+		<image rdf:resource="https://example.com/logo.png"/>
+	</channel>
+	<item rdf:about="https://example.com/2">
+		<title>Second</title>
+		<link> https://example.com/2 </link>
+		<description>&lt;p&gt;Hi&lt;/p&gt;</description>
+		<content:encoded>&lt;p&gt;Hi there&lt;/p&gt;</content:encoded>
+		<dc:creator>Tom</dc:creator>
+		<dc:subject>cats</dc:subject>
+		<dc:subject> </dc:subject>
+		<dc:subject>mice</dc:subject>
+		<dc:date>2024-05-01T10:00:00+02:00</dc:date>
+	</item>
+</rdf:RDF>`,
+			expected: Feed{
+				Title:    "Tom & Jerry",
+				Link:     "https://example.com/",
+				Image:    "https://example.com/logo.png",
+				Language: "en",
+				Items: []Item{
+					{
+						ID:          "https://example.com/2",
+						Title:       "Second",
+						Link:        "https://example.com/2",
+						Description: "<p>Hi</p>",
+						Content:     "<p>Hi there</p>",
+						Author:      "Tom",
+						Categories:  []string{"cats", "mice"},
+						DCDate:      "2024-05-01T10:00:00+02:00",
+					},
+				},
+			},
+		},
+		{
+			name: "media siblings",
+			body: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"
+	xmlns:media="http://search.yahoo.com/mrss/">
+	<channel rdf:about="https://example.com/">
+		<title>Example</title>
+	</channel>
+	<item rdf:about="https://example.com/3">
+		<title>The real title</title>
+		<link>https://example.com/3</link>
+		<description>The real description</description>
+		<media:title>A caption</media:title>
+		<media:description>About the picture</media:description>
+	</item>
+</rdf:RDF>`,
+			expected: Feed{
+				Title: "Example",
+				Items: []Item{
+					{
+						ID:          "https://example.com/3",
+						Title:       "The real title",
+						Link:        "https://example.com/3",
+						Description: "The real description",
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := parseRDF(newXMLDecoder([]byte(c.body)))
+		if err != nil {
+			t.Errorf("%s: Expected no error but got %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(*actual, c.expected) {
+			t.Errorf("%s: Expected %+v but got %+v", c.name, c.expected, *actual)
+		}
+	}
+}
+
+func TestParseFeedRDF(t *testing.T) {
+	body := []byte(`<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+	<channel rdf:about="https://example.com/">
+		<title>Example</title>
+	</channel>
+	<image rdf:about="https://example.com/logo.png">
+		<url>https://example.com/logo.png</url>
+	</image>
+	<item rdf:about="https://example.com/1">
+		<title>First</title>
+		<dc:date>2024-05-01T10:00:00Z</dc:date>
+	</item>
+</rdf:RDF>`)
+
+	feed, err := parseFeed(body, "application/rdf+xml", "")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	if feed.Image != "https://example.com/logo.png" {
+		t.Errorf("Expected %v but got %v", "https://example.com/logo.png", feed.Image)
+	}
+
+	if len(feed.Items) != 1 {
+		t.Fatalf("Expected 1 item but got %d", len(feed.Items))
+	}
+
+	expected := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+	published, ok := feed.Items[0].Published()
+	if !ok || !published.Equal(expected) {
+		t.Errorf("Expected %v but got %v", expected, published)
+	}
+
+	// An RDF root from some other vocabulary isn't a feed.
+	_, err = parseFeed([]byte(`<RDF xmlns="https://example.com/not-rdf"><channel/></RDF>`), "", "")
+	if err == nil || err.Error() != "unsupported feed format: <RDF>" {
+		t.Errorf("Expected %v but got %v", "unsupported feed format: <RDF>", err)
+	}
+}
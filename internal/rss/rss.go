@@ -1,62 +1,383 @@
 package rss
 
 import (
-	"context"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"html"
 	"io"
-	"net/http"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// ErrHTMLPage is returned when a URL leads to a web page rather than a feed.
+// Discover can find the feeds the page links to.
+var ErrHTMLPage = errors.New("got a web page instead of a feed")
+
+// Feed is the format independent view of a fetched feed that the aggregator
+// works with, whichever format the publisher used.
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	// Image is the URL of the publisher's logo or icon.
+	Image    string
+	Language string
+	// ParseMode says whether the feed had to be parsed leniently.
+	ParseMode ParseMode
+	Items     []Item
+	// base is the feed's own xml:base, until resolveLinks has used it.
+	base string
+}
+
+type Item struct {
+	ID    string
+	Title string
+	Link  string
+	// Description is HTML, left escaped the way the publisher sent it.
+	Description string
+	// Content is the full body when the feed carries one separately from
+	// the description, like content:encoded.
+	Content    string
+	Author     string
+	Categories []string
+	Comments   string
+	Enclosures []Enclosure
+	// Podcast is set for items that carry an audio or video episode.
+	Podcast *PodcastEpisode
+	PubDate string
+	DCDate  string
+	Updated string
+	// Base is the URL that relative links in the description and content
+	// are relative to.
+	Base string
+	// link is Link as the feed wrote it, before resolveLinks made it
+	// absolute. Keys are built from it so they don't change along with it.
+	link string
+}
+
+// Enclosure is a media file attached to an item. Length is in bytes and
+// zero when unknown.
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+// Key identifies the item within its feed. It is the item's guid or Atom
+// id if it has one, then its link as the feed wrote it, and for items with
+// neither a hash of the title and description.
+func (i Item) Key() string {
+	if id := strings.TrimSpace(i.ID); id != "" {
+		return id
+	}
+
+	link := i.Link
+	if i.link != "" {
+		link = i.link
+	}
+	if link = strings.TrimSpace(link); link != "" {
+		return link
+	}
+
+	sum := sha256.Sum256([]byte(i.Title + "\x00" + i.Description))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// LegacyKey is what the item was keyed on before Key existed: its link as
+// the feed wrote it. Posts stored back then have it as both guid and url.
+func (i Item) LegacyKey() string {
+	link := i.Link
+	if i.link != "" {
+		link = i.link
+	}
+
+	return strings.TrimSpace(link)
+}
+
+// Published returns when the item was published, falling back from the
+// pubDate to dc:date and then to the updated date. It reports false if
+// none of them can be parsed.
+func (i Item) Published() (time.Time, bool) {
+	for _, value := range []string{i.PubDate, i.DCDate, i.Updated} {
+		t, err := ParseDate(value)
+		if err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
 type RSSFeed struct {
+	Base    string `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
 	Channel struct {
-		Title       string    `xml:"title"`
-		Link        string    `xml:"link"`
-		Description string    `xml:"description"`
-		Item        []RSSItem `xml:"item"`
+		Base        string  `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+		Title       rssText `xml:"title"`
+		Link        rssText `xml:"link"`
+		Description rssText `xml:"description"`
+		Language    rssText `xml:"language"`
+		// ItunesImage has to come before Image, which would otherwise be
+		// handed <itunes:image> first and skip it.
+		ItunesImage itunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		Image       rssImage    `xml:"image"`
+		Item        []RSSItem   `xml:"item"`
 	} `xml:"channel"`
 }
 
 type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	Base           string         `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	GUID           rssText        `xml:"guid"`
+	Title          rssText        `xml:"title"`
+	Link           rssText        `xml:"link"`
+	Description    rssText        `xml:"description"`
+	ContentEncoded string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Author         rssText        `xml:"author"`
+	Creator        string         `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories     []rssText      `xml:"category"`
+	Comments       rssText        `xml:"comments"`
+	Enclosures     []RSSEnclosure `xml:"enclosure"`
+	PubDate        rssText        `xml:"pubDate"`
+	DCDate         string         `xml:"http://purl.org/dc/elements/1.1/ date"`
+	ItunesDuration string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ItunesEpisode  string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	ItunesSeason   string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+	ItunesImage    itunesImage    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	ItunesExplicit string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd explicit"`
+}
+
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
 }
 
-func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+// rssText is the text of an RSS element, which has no namespace. Go matches
+// tags without a namespace against elements in every namespace, so without
+// this <atom:link> would overwrite <link> and <itunes:author> <author>.
+type rssText string
+
+func (t *rssText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if start.Name.Space != "" {
+		return d.Skip()
+	}
+
+	var s string
+	err := d.DecodeElement(&s, &start)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	client := &http.Client{}
+	*t = rssText(s)
+	return nil
+}
+
+func (t rssText) String() string {
+	return strings.TrimSpace(string(t))
+}
+
+// rssImage is the channel's <image>, kept apart from <itunes:image> the
+// same way rssText is.
+type rssImage struct {
+	URL rssText `xml:"url"`
+}
+
+func (i *rssImage) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if start.Name.Space != "" {
+		return d.Skip()
+	}
+
+	type plain rssImage
+	return d.DecodeElement((*plain)(i), &start)
+}
+
+// parseFeed works out which format the body is in and hands it to the
+// matching parser. JSON Feeds are recognised by their Content-Type or by
+// looking like a JSON object, XML formats by their root element once they
+// have been converted to UTF-8. XML that the strict parser rejects is given
+// a second chance with the lenient one. Relative links are resolved
+// against feedURL, the address the feed was fetched from.
+func parseFeed(body []byte, contentType, feedURL string) (*Feed, error) {
+	if isJSON(body, contentType) {
+		feed, err := parseJSONFeed(body)
+		if err != nil {
+			return nil, err
+		}
 
-	req.Header.Set("User-Agent", "Gator RSS Feed Reader")
+		feed.ParseMode = ParseStrict
+		feed.resolveLinks(feedURL)
+		return feed, nil
+	}
 
-	resp, err := client.Do(req)
+	body, err := toUTF8(body, contentType)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	if hasHTMLDoctype(body) {
+		return nil, ErrHTMLPage
+	}
+
+	feed, err := parseXML(body, contentType, newXMLDecoder)
+	if err == nil {
+		feed.ParseMode = ParseStrict
+		feed.resolveLinks(feedURL)
+		return feed, nil
+	}
+	if errors.Is(err, ErrHTMLPage) {
+		return nil, err
+	}
+
+	feed, lenientErr := parseXML(body, contentType, newLenientDecoder)
+	if lenientErr != nil {
+		// The strict parser's complaint is the one worth fixing.
+		return nil, err
+	}
+
+	feed.ParseMode = ParseLenient
+	feed.resolveLinks(feedURL)
+	return feed, nil
+}
+
+func parseXML(body []byte, contentType string, newDecoder func([]byte) *xml.Decoder) (*Feed, error) {
+	// Some servers send feeds as text/html, so only trust the Content-Type
+	// once the body turns out not to be a feed either.
+	root, err := rootElement(newDecoder(body))
 	if err != nil {
+		if isHTMLType(contentType) {
+			return nil, ErrHTMLPage
+		}
 		return nil, err
 	}
 
+	switch root.Local {
+	case "rss":
+		return parseRSS(newDecoder(body))
+	case "feed":
+		return parseAtom(newDecoder(body))
+	case "RDF":
+		if root.Space != rdfNamespace {
+			return nil, fmt.Errorf("unsupported feed format: <%s>", root.Local)
+		}
+		return parseRDF(newDecoder(body))
+	default:
+		if root.Local == "html" || isHTMLType(contentType) {
+			return nil, ErrHTMLPage
+		}
+		return nil, fmt.Errorf("unsupported feed format: <%s>", root.Local)
+	}
+}
+
+func isJSON(body []byte, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && (mediaType == "application/feed+json" || mediaType == "application/json") {
+		return true
+	}
+
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")), " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// hasHTMLDoctype spots web pages before they reach the XML decoder, which
+// would usually choke on them rather than report an <html> root.
+func hasHTMLDoctype(body []byte) bool {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")), " \t\r\n")
+	return len(trimmed) >= 14 && bytes.EqualFold(trimmed[:14], []byte("<!doctype html"))
+}
+
+func isHTMLType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+func rootElement(decoder *xml.Decoder) (xml.Name, error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return xml.Name{}, errors.New("feed has no root element")
+			}
+			return xml.Name{}, err
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name, nil
+		}
+	}
+}
+
+func parseRSS(decoder *xml.Decoder) (*Feed, error) {
 	var feed RSSFeed
-	err = xml.Unmarshal(body, &feed)
+	err := decoder.Decode(&feed)
 	if err != nil {
 		return nil, err
 	}
 
-	feed.Channel.Title = html.UnescapeString(feed.Channel.Title)
-	feed.Channel.Description = html.UnescapeString(feed.Channel.Description)
-	for i := range feed.Channel.Item {
-		feed.Channel.Item[i].Title = html.UnescapeString(feed.Channel.Item[i].Title)
-		feed.Channel.Item[i].Description = html.UnescapeString(feed.Channel.Item[i].Description)
+	return feed.normalize(), nil
+}
+
+func (f *RSSFeed) normalize() *Feed {
+	feed := &Feed{
+		Title:       html.UnescapeString(f.Channel.Title.String()),
+		Link:        f.Channel.Link.String(),
+		Description: html.UnescapeString(f.Channel.Description.String()),
+		Image:       f.Channel.Image.URL.String(),
+		Language:    f.Channel.Language.String(),
+		base:        joinBase(f.Base, f.Channel.Base),
+	}
+
+	showImage := strings.TrimSpace(f.Channel.ItunesImage.Href)
+	if feed.Image == "" {
+		feed.Image = showImage
+	}
+	for _, item := range f.Channel.Item {
+		author := item.Author.String()
+		if author == "" {
+			author = strings.TrimSpace(item.Creator)
+		}
+
+		var categories []string
+		for _, category := range item.Categories {
+			if category.String() != "" {
+				categories = append(categories, category.String())
+			}
+		}
+
+		var enclosures []Enclosure
+		for _, enclosure := range item.Enclosures {
+			if strings.TrimSpace(enclosure.URL) == "" {
+				continue
+			}
+
+			length, _ := strconv.ParseInt(strings.TrimSpace(enclosure.Length), 10, 64)
+			enclosures = append(enclosures, Enclosure{
+				URL:    strings.TrimSpace(enclosure.URL),
+				Type:   strings.TrimSpace(enclosure.Type),
+				Length: length,
+			})
+		}
+
+		feed.Items = append(feed.Items, Item{
+			ID:          item.GUID.String(),
+			Title:       html.UnescapeString(item.Title.String()),
+			Link:        item.Link.String(),
+			Description: item.Description.String(),
+			Content:     strings.TrimSpace(item.ContentEncoded),
+			Author:      author,
+			Categories:  categories,
+			Comments:    item.Comments.String(),
+			Enclosures:  enclosures,
+			Podcast:     item.podcastEpisode(showImage),
+			PubDate:     item.PubDate.String(),
+			DCDate:      item.DCDate,
+			Base:        strings.TrimSpace(item.Base),
+		})
 	}
 
-	return &feed, nil
+	return feed
 }
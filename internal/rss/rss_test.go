@@ -0,0 +1,121 @@
+package rss
+
+import "testing"
+
+func TestItemLegacyKey(t *testing.T) {
+	body := []byte(`<rss version="2.0">
+<channel>
+	<title>Blog</title>
+	<link>https://example.com/</link>
+	<item>
+		<title>Guid that isn't the link</title>
+		<guid isPermaLink="false">https://example.com/?p=42</guid>
+		<link>/2024/hello/</link>
+	</item>
+	<item>
+		<title>No guid</title>
+		<link>/2024/world/</link>
+	</item>
+	<item>
+		<title>No link</title>
+		<guid isPermaLink="false">urn:uuid:1b4e28ba-2fa1-11d2-883f-0016d3cca427</guid>
+	</item>
+</channel>
+</rss>`)
+
+	feed, err := parseFeed(body, "application/rss+xml", "https://example.com/feed/")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	// Posts stored before items had keys have the legacy key as both guid
+	// and url, and get adopted when it differs from the key.
+	cases := []struct {
+		key       string
+		legacyKey string
+	}{
+		{key: "https://example.com/?p=42", legacyKey: "/2024/hello/"},
+		{key: "/2024/world/", legacyKey: "/2024/world/"},
+		{key: "urn:uuid:1b4e28ba-2fa1-11d2-883f-0016d3cca427", legacyKey: ""},
+	}
+
+	if len(feed.Items) != len(cases) {
+		t.Fatalf("Expected %d items but got %d", len(cases), len(feed.Items))
+	}
+
+	for i, c := range cases {
+		item := feed.Items[i]
+		if item.Key() != c.key {
+			t.Errorf("Expected key %q but got %q", c.key, item.Key())
+		}
+		if item.LegacyKey() != c.legacyKey {
+			t.Errorf("Expected legacy key %q but got %q", c.legacyKey, item.LegacyKey())
+		}
+	}
+}
+
+func TestParseFeedFormat(t *testing.T) {
+	cases := []struct {
+		name        string
+		body        string
+		contentType string
+		expected    string
+		err         string
+	}{
+		{
+			name:     "rss",
+			body:     `<rss version="2.0"><channel><title>RSS</title></channel></rss>`,
+			expected: "RSS",
+		},
+		{
+			name:        "atom",
+			body:        `<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>Atom</title></feed>`,
+			contentType: "application/atom+xml",
+			expected:    "Atom",
+		},
+		{
+			name:        "feed sent as text/html",
+			body:        `<feed xmlns="http://www.w3.org/2005/Atom"><title>Atom</title></feed>`,
+			contentType: "text/html; charset=utf-8",
+			expected:    "Atom",
+		},
+		{
+			name: "web page",
+			body: `<!DOCTYPE html><html><head><title>Home</title></head></html>`,
+			err:  ErrHTMLPage.Error(),
+		},
+		{
+			name: "html root",
+			body: `<html><head><title>Home</title></head></html>`,
+			err:  ErrHTMLPage.Error(),
+		},
+		{
+			name: "unknown root",
+			body: `<opml version="2.0"><head><title>Subscriptions</title></head></opml>`,
+			err:  "unsupported feed format: <opml>",
+		},
+		{
+			name:        "unknown root sent as text/html",
+			body:        `<opml version="2.0"><head><title>Subscriptions</title></head></opml>`,
+			contentType: "text/html",
+			err:         ErrHTMLPage.Error(),
+		},
+	}
+
+	for _, c := range cases {
+		feed, err := parseFeed([]byte(c.body), c.contentType, "")
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("%s: Expected %v but got %v", c.name, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Expected no error but got %v", c.name, err)
+			continue
+		}
+		if feed.Title != c.expected {
+			t.Errorf("%s: Expected %q but got %q", c.name, c.expected, feed.Title)
+		}
+	}
+}
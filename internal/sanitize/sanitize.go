@@ -0,0 +1,283 @@
+package sanitize
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedTags lists the elements that are kept along with the attributes
+// each may keep. Other elements are unwrapped, so their text survives.
+var allowedTags = map[atom.Atom][]string{
+	atom.A:          {"href"},
+	atom.Abbr:       nil,
+	atom.Audio:      {"src", "controls"},
+	atom.B:          nil,
+	atom.Blockquote: {"cite"},
+	atom.Br:         nil,
+	atom.Caption:    nil,
+	atom.Cite:       nil,
+	atom.Code:       nil,
+	atom.Dd:         nil,
+	atom.Del:        nil,
+	atom.Details:    nil,
+	atom.Div:        nil,
+	atom.Dl:         nil,
+	atom.Dt:         nil,
+	atom.Em:         nil,
+	atom.Figcaption: nil,
+	atom.Figure:     nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Hr:         nil,
+	atom.I:          nil,
+	atom.Img:        {"src", "alt", "width", "height"},
+	atom.Ins:        nil,
+	atom.Kbd:        nil,
+	atom.Li:         nil,
+	atom.Mark:       nil,
+	atom.Ol:         {"start"},
+	atom.P:          nil,
+	atom.Pre:        nil,
+	atom.Q:          {"cite"},
+	atom.S:          nil,
+	atom.Small:      nil,
+	atom.Source:     {"src", "type"},
+	atom.Span:       nil,
+	atom.Strong:     nil,
+	atom.Sub:        nil,
+	atom.Summary:    nil,
+	atom.Sup:        nil,
+	atom.Table:      nil,
+	atom.Tbody:      nil,
+	atom.Td:         {"colspan", "rowspan"},
+	atom.Tfoot:      nil,
+	atom.Th:         {"colspan", "rowspan"},
+	atom.Thead:      nil,
+	atom.Time:       {"datetime"},
+	atom.Tr:         nil,
+	atom.U:          nil,
+	atom.Ul:         nil,
+	atom.Video:      {"src", "poster", "controls"},
+}
+
+// globalAttrs may appear on any allowed element.
+var globalAttrs = []string{"title", "lang", "dir"}
+
+// droppedTags are removed together with everything inside them.
+var droppedTags = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Iframe:   true,
+	atom.Frame:    true,
+	atom.Frameset: true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Applet:   true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Form:     true,
+	atom.Input:    true,
+	atom.Button:   true,
+	atom.Select:   true,
+	atom.Textarea: true,
+	atom.Svg:      true,
+	atom.Math:     true,
+	atom.Head:     true,
+	atom.Title:    true,
+	atom.Meta:     true,
+	atom.Link:     true,
+	atom.Base:     true,
+}
+
+// urlAttrs hold URLs, which are made absolute and limited to safe schemes.
+var urlAttrs = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"cite":   true,
+	"poster": true,
+}
+
+var voidTags = map[atom.Atom]bool{
+	atom.Br:     true,
+	atom.Hr:     true,
+	atom.Img:    true,
+	atom.Source: true,
+}
+
+// HTML cleans up an HTML fragment from a feed so it is safe to show in a
+// browser. Only a known set of tags and attributes is kept, scripts and
+// anything else that can run code are removed along with event handlers
+// and styles, and so are tracking pixels. Relative URLs are resolved
+// against base, which may be nil, and any URL that isn't http, https or
+// mailto is dropped.
+func HTML(fragment string, base *url.URL) string {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), body)
+	if err != nil {
+		return html.EscapeString(fragment)
+	}
+
+	s := &sanitizer{base: base}
+	for _, node := range nodes {
+		s.walk(node)
+	}
+
+	return strings.TrimSpace(s.out.String())
+}
+
+type sanitizer struct {
+	base *url.URL
+	out  strings.Builder
+}
+
+func (s *sanitizer) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		s.out.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		// Comments and doctypes have no business in a post.
+		return
+	}
+
+	if droppedTags[n.DataAtom] {
+		return
+	}
+
+	allowed, ok := allowedTags[n.DataAtom]
+	if !ok || n.DataAtom == 0 {
+		s.children(n)
+		return
+	}
+
+	attrs, keep := s.attributes(n, allowed)
+	if !keep {
+		return
+	}
+
+	s.out.WriteString("<" + n.Data)
+	for _, a := range attrs {
+		s.out.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+	}
+	s.out.WriteString(">")
+
+	if voidTags[n.DataAtom] {
+		return
+	}
+
+	s.children(n)
+	s.out.WriteString("</" + n.Data + ">")
+}
+
+func (s *sanitizer) children(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		s.walk(child)
+	}
+}
+
+// attributes returns the attributes of n that may be kept. It reports false
+// when the element itself should go, like an image with no usable source
+// or one that is a tracking pixel.
+func (s *sanitizer) attributes(n *html.Node, allowed []string) ([]html.Attribute, bool) {
+	var attrs []html.Attribute
+	for _, a := range n.Attr {
+		key := strings.ToLower(a.Key)
+		if a.Namespace != "" || !(slices.Contains(allowed, key) || slices.Contains(globalAttrs, key)) {
+			continue
+		}
+
+		value := a.Val
+		if urlAttrs[key] {
+			var ok bool
+			value, ok = s.resolve(value, key == "href")
+			if !ok {
+				continue
+			}
+		}
+
+		attrs = append(attrs, html.Attribute{Key: key, Val: value})
+	}
+
+	switch n.DataAtom {
+	case atom.Img:
+		if !hasAttr(attrs, "src") || isTrackingPixel(attrs) {
+			return nil, false
+		}
+	case atom.Source:
+		if !hasAttr(attrs, "src") {
+			return nil, false
+		}
+	}
+
+	return attrs, true
+}
+
+// resolve makes a URL absolute and checks its scheme. Links may also be
+// mailto: addresses, everything else has to be http or https.
+func (s *sanitizer) resolve(raw string, isLink bool) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", false
+	}
+
+	// Leave links within the post alone, there is nothing to resolve.
+	if isLink && u.Scheme == "" && u.Host == "" && u.Path == "" && u.RawQuery == "" {
+		return u.String(), u.Fragment != ""
+	}
+
+	if s.base != nil {
+		u = s.base.ResolveReference(u)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.String(), true
+	case "mailto":
+		return u.String(), isLink
+	case "":
+		// Without a base a relative URL is the best there is.
+		return u.String(), s.base == nil
+	default:
+		return "", false
+	}
+}
+
+// isTrackingPixel spots the invisible 1x1 images used to count readers.
+func isTrackingPixel(attrs []html.Attribute) bool {
+	width, height := -1, -1
+	for _, a := range attrs {
+		size, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(a.Val), "px"))
+		if err != nil {
+			continue
+		}
+
+		switch a.Key {
+		case "width":
+			width = size
+		case "height":
+			height = size
+		}
+	}
+
+	return width >= 0 && width <= 1 && height >= 0 && height <= 1
+}
+
+func hasAttr(attrs []html.Attribute, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
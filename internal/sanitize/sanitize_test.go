@@ -0,0 +1,69 @@
+package sanitize
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	base, _ := url.Parse("https://example.com/posts/123")
+
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "safe markup is kept",
+			input:    `<p>Some <strong>bold</strong> and <em>leaning</em> text</p><ul><li>one</li></ul>`,
+			expected: `<p>Some <strong>bold</strong> and <em>leaning</em> text</p><ul><li>one</li></ul>`,
+		},
+		{
+			name:     "scripts and styles are removed",
+			input:    `<p>Hi<script>alert(1)</script><style>p { color: red }</style></p><iframe src="https://evil.example"></iframe>`,
+			expected: `<p>Hi</p>`,
+		},
+		{
+			name:     "event handlers and styles are stripped",
+			input:    `<p onclick="alert(1)" style="color: red" class="x" title="t">Hi</p><img src="/a.png" onerror="alert(1)" alt="A">`,
+			expected: `<p title="t">Hi</p><img src="https://example.com/a.png" alt="A">`,
+		},
+		{
+			name:     "javascript urls are dropped",
+			input:    `<a href="javascript:alert(1)">one</a><a href=" JavaScript:alert(1)">two</a><img src="data:image/png;base64,AAAA">`,
+			expected: `<a>one</a><a>two</a>`,
+		},
+		{
+			name:     "relative urls are resolved",
+			input:    `<a href="/about">About</a> <a href="next">Next</a> <a href="#notes">Notes</a> <a href="mailto:me@example.com">Mail</a>`,
+			expected: `<a href="https://example.com/about">About</a> <a href="https://example.com/posts/next">Next</a> <a href="#notes">Notes</a> <a href="mailto:me@example.com">Mail</a>`,
+		},
+		{
+			name:     "tracking pixels are removed",
+			input:    `<p>Hi<img src="https://t.example/p.gif" width="1" height="1"><img src="https://t.example/q.gif" width="0" height="0px"><img src="https://example.com/big.png" width="1" height="400"></p>`,
+			expected: `<p>Hi<img src="https://example.com/big.png" width="1" height="400"></p>`,
+		},
+		{
+			name:     "unknown tags are unwrapped",
+			input:    `<center><font color="red">Old <blink>school</blink></font></center>`,
+			expected: `Old school`,
+		},
+		{
+			name:     "text is escaped",
+			input:    `Tom &amp; Jerry &lt;script&gt;alert(1)&lt;/script&gt;<!-- comment -->`,
+			expected: `Tom &amp; Jerry &lt;script&gt;alert(1)&lt;/script&gt;`,
+		},
+		{
+			name:     "attributes are escaped",
+			input:    `<img src="https://example.com/a.png" alt="&quot; onerror=&quot;alert(1)">`,
+			expected: `<img src="https://example.com/a.png" alt="&#34; onerror=&#34;alert(1)">`,
+		},
+	}
+
+	for _, c := range cases {
+		actual := HTML(c.input, base)
+		if actual != c.expected {
+			t.Errorf("%s: Expected %q but got %q", c.name, c.expected, actual)
+		}
+	}
+}
@@ -1,24 +1,47 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/l2thet/Gator/internal/config"
 	"github.com/l2thet/Gator/internal/database"
+	"github.com/l2thet/Gator/internal/htmltext"
 	"github.com/l2thet/Gator/internal/rss"
-	"github.com/lib/pq"
+	"github.com/l2thet/Gator/internal/sanitize"
+	_ "github.com/lib/pq"
+	"golang.org/x/term"
+)
+
+const (
+	defaultAggInterval  = time.Minute
+	defaultPodcastLimit = 5
+	// defaultTerminalWidth is used when output doesn't go to a terminal
+	// and COLUMNS isn't set.
+	defaultTerminalWidth = 80
 )
 
 type State struct {
-	db  *database.Queries
-	cfg *config.Config
+	db      *database.Queries
+	dbConn  *sql.DB
+	cfg     *config.Config
+	fetcher *rss.Fetcher
 }
 
 type Command struct {
@@ -44,6 +67,21 @@ func main() {
 	}
 
 	s.db = database.New(db)
+	s.dbConn = db
+
+	s.fetcher, err = rss.NewFetcher(rss.FetcherOptions{
+		ConnectTimeout: time.Duration(s.cfg.ConnectTimeout),
+		Timeout:        time.Duration(s.cfg.FetchTimeout),
+		MaxBodySize:    s.cfg.MaxFeedSize,
+		Proxy:          s.cfg.HTTPProxy,
+		UserAgent:      s.cfg.UserAgent,
+		AllowedHosts:   s.cfg.AllowedHosts,
+		HostMinDelay:   time.Duration(s.cfg.HostMinDelay),
+		HostBurst:      s.cfg.HostBurst,
+	})
+	if err != nil {
+		log.Fatalf("Error setting up feed fetcher: %v", err)
+	}
 
 	cmds := &Commands{
 		callback: make(map[string]func(*State, Command) error),
@@ -60,6 +98,9 @@ func main() {
 	cmds.register("following", middlewareLoggedIn(handlerFollowing))
 	cmds.register("unfollow", middlewareLoggedIn(handlerUnfollow))
 	cmds.register("browse", middlewareLoggedIn(handlerBrowse))
+	cmds.register("feedhealth", handlerFeedHealth)
+	cmds.register("feed", handlerFeed)
+	cmds.register("podcasts", middlewareLoggedIn(handlerPodcasts))
 
 	args := os.Args
 	if len(args) < 2 {
@@ -101,69 +142,517 @@ func middlewareLoggedIn(handler func(s *State, cmd Command, user database.User)
 	}
 }
 
-func scrapeFeeds(s *State) error {
-	err := s.db.ResetFeedsToFetch(context.Background())
+// feedResult is what a worker reports back after scraping a single feed.
+type feedResult struct {
+	feed        database.Feed
+	notModified bool
+	postponed   bool
+	inserted    int
+	updated     int
+	err         error
+}
+
+func scrapeFeeds(ctx context.Context, s *State) error {
+	feeds, err := s.db.GetFeedstoFetch(ctx)
 	if err != nil {
-		err := fmt.Errorf("error resetting feeds to fetch: %v", err)
+		err := fmt.Errorf("error getting feeds to fetch: %v", err)
 		return err
 	}
 
-	feeds, err := s.db.GetFeedstoFetch(context.Background())
+	jobs := make(chan database.Feed)
+	results := make(chan feedResult)
+
+	go func() {
+		defer close(jobs)
+		for _, feed := range feeds {
+			select {
+			case jobs <- feed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Every worker writes through the shared *sql.DB pool, which is safe for
+	// concurrent use, and only ever touches the feed it was handed.
+	var wg sync.WaitGroup
+	for i := 0; i < s.cfg.Concurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for feed := range jobs {
+				results <- scrapeFeed(ctx, s, feed)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var fetched, failed, postponed, inserted, updated int
+	for result := range results {
+		if result.postponed {
+			postponed++
+			fmt.Printf("* %s: postponed: %v\n", result.feed.Name, result.err)
+			continue
+		}
+
+		if result.err != nil {
+			failed++
+			fmt.Printf("* %s: failed: %v\n", result.feed.Name, result.err)
+			continue
+		}
+
+		fetched++
+		if result.notModified {
+			fmt.Printf("* %s: not modified\n", result.feed.Name)
+			continue
+		}
+
+		inserted += result.inserted
+		updated += result.updated
+		fmt.Printf("* %s: %d new posts, %d updated\n", result.feed.Name, result.inserted, result.updated)
+	}
+
+	fmt.Printf("Fetched %d feeds, %d failed, %d postponed, %d posts inserted, %d updated\n", fetched, failed, postponed, inserted, updated)
+
+	return ctx.Err()
+}
+
+// scrapeFeed fetches a single feed and stores its posts. Any error is saved
+// on the feed row instead of being passed up, so one broken feed can't stop
+// the rest of the run.
+func scrapeFeed(ctx context.Context, s *State, feed database.Feed) feedResult {
+	result := feedResult{feed: feed}
+
+	fetched, err := s.fetcher.Fetch(ctx, feed.Url, rss.Cache{
+		ETag:         feed.Etag.String,
+		LastModified: feed.LastModified.String,
+	})
+	var pausedErr *rss.HostPausedError
+	if errors.As(err, &pausedErr) {
+		// The host asked for a break, which says nothing about the feed.
+		postponeFeed(ctx, s, feed, pausedErr.Until)
+		result.postponed = true
+		result.err = err
+		return result
+	}
+
+	var statusErr *rss.StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		// Neither does a 429 or 503 telling us when to come back.
+		postponeFeed(ctx, s, feed, time.Now().Add(statusErr.RetryAfter))
+		result.postponed = true
+		result.err = err
+		return result
+	}
+
+	if err != nil {
+		result.err = fmt.Errorf("error fetching feed: %w", err)
+	} else if fetched.NotModified {
+		result.notModified = true
+	} else {
+		result.inserted, result.updated, result.err = storeFeedPosts(ctx, s, feed, fetched.Feed)
+	}
+
+	if result.err != nil {
+		// A shutdown isn't the feed's fault, so don't count it against it.
+		if ctx.Err() != nil {
+			return result
+		}
+
+		markFeedFailed(ctx, s, feed, result.err)
+		return result
+	}
+
+	minInterval, maxInterval := s.cfg.FetchIntervalBounds()
+	interval := nextFetchInterval(time.Duration(feed.FetchIntervalSeconds)*time.Second, result.inserted, minInterval, maxInterval)
+
+	err = s.db.MarkFeedFetched(ctx, database.MarkFeedFetchedParams{
+		ID:                   feed.ID,
+		Etag:                 sql.NullString{String: fetched.Cache.ETag, Valid: fetched.Cache.ETag != ""},
+		LastModified:         sql.NullString{String: fetched.Cache.LastModified, Valid: fetched.Cache.LastModified != ""},
+		FetchIntervalSeconds: int32(interval / time.Second),
+		NextFetchAt:          sql.NullTime{Time: time.Now().Add(interval), Valid: true},
+	})
+	if err != nil {
+		result.err = fmt.Errorf("error marking feed fetched: %v", err)
+		return result
+	}
+
+	if !result.notModified {
+		err = updateFeedMetadata(ctx, s, feed.ID, fetched.Feed)
+		if err != nil {
+			fmt.Printf("Error updating metadata for feed %s: %v\n", feed.Name, err)
+		}
+	}
+
+	if fetched.PermanentURL != "" && fetched.PermanentURL != feed.Url {
+		err = moveFeed(ctx, s, feed, fetched.PermanentURL)
+		if err != nil {
+			fmt.Printf("Error moving feed %s to %s: %v\n", feed.Name, fetched.PermanentURL, err)
+		}
+	}
+
+	return result
+}
+
+// postponeFeed puts off the next fetch of a feed without counting it as a
+// failure, for when the host rather than the feed is the problem. It is
+// never put off for longer than the longest fetch interval, whatever the
+// host asked for, and feedhealth shows feeds that keep being postponed.
+func postponeFeed(ctx context.Context, s *State, feed database.Feed, until time.Time) {
+	_, maxInterval := s.cfg.FetchIntervalBounds()
+
+	err := s.db.RescheduleFeed(ctx, database.RescheduleFeedParams{
+		ID:          feed.ID,
+		NextFetchAt: sql.NullTime{Time: postponeUntil(until, time.Now(), maxInterval), Valid: true},
+	})
+	if err != nil {
+		fmt.Printf("Error rescheduling feed %s: %v\n", feed.Name, err)
+	}
+}
+
+// postponeUntil keeps a Retry-After from putting a feed off for longer than
+// maxInterval, like the years a bogus header can ask for.
+func postponeUntil(until, now time.Time, maxInterval time.Duration) time.Time {
+	if latest := now.Add(maxInterval); until.After(latest) {
+		return latest
+	}
+
+	return until
+}
+
+// updateFeedMetadata stores what the publisher says about the feed itself,
+// so it follows along when they rename or rebrand it.
+func updateFeedMetadata(ctx context.Context, s *State, feedID uuid.UUID, parsed *rss.Feed) error {
+	return s.db.UpdateFeedMetadata(ctx, database.UpdateFeedMetadataParams{
+		ID:          feedID,
+		Title:       nullString(parsed.Title),
+		SiteUrl:     nullString(parsed.Link),
+		Description: nullString(parsed.Description),
+		ImageUrl:    nullString(parsed.Image),
+		Language:    nullString(parsed.Language),
+		ParseMode:   nullString(string(parsed.ParseMode)),
+	})
+}
+
+// moveFeed points a feed at the URL it was permanently redirected to. If
+// another feed already has that URL the two are merged: followers and posts
+// move over to the existing feed and this one is deleted.
+func moveFeed(ctx context.Context, s *State, feed database.Feed, newURL string) error {
+	tx, err := s.dbConn.BeginTx(ctx, nil)
 	if err != nil {
-		err := fmt.Errorf("error getting feeds to fetch: %v", err)
 		return err
 	}
+	defer tx.Rollback()
 
-	for _, feed := range feeds {
-		articles, err := rss.FetchFeed(context.Background(), feed.Url)
+	qtx := s.db.WithTx(tx)
+
+	existingID, err := qtx.GetFeedIdByUrl(ctx, newURL)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = qtx.UpdateFeedUrl(ctx, database.UpdateFeedUrlParams{
+			ID:  feed.ID,
+			Url: newURL,
+		})
 		if err != nil {
-			err := fmt.Errorf("error fetching feed: %v", err)
-			return err
+			return fmt.Errorf("error updating feed url: %v", err)
+		}
+
+		fmt.Printf("Feed %s has moved to %s\n", feed.Name, newURL)
+		return tx.Commit()
+	}
+	if err != nil {
+		return fmt.Errorf("error getting feed id by url: %v", err)
+	}
+
+	err = qtx.MoveFeedFollows(ctx, database.MoveFeedFollowsParams{
+		ToFeedID:   existingID,
+		FromFeedID: feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error moving feed follows: %v", err)
+	}
+
+	err = qtx.MoveFeedPosts(ctx, database.MoveFeedPostsParams{
+		ToFeedID:   existingID,
+		FromFeedID: feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error moving feed posts: %v", err)
+	}
+
+	// Whatever is left over is already in the other feed.
+	err = qtx.DeleteFeedPosts(ctx, feed.ID)
+	if err != nil {
+		return fmt.Errorf("error deleting feed posts: %v", err)
+	}
+
+	err = qtx.DeleteFeed(ctx, feed.ID)
+	if err != nil {
+		return fmt.Errorf("error deleting feed: %v", err)
+	}
+
+	fmt.Printf("Feed %s has moved to %s and was merged into the feed already there\n", feed.Name, newURL)
+	return tx.Commit()
+}
+
+// markFeedFailed records a failed fetch and backs the feed off exponentially,
+// so a broken feed is retried less and less often. It gets disabled once it
+// has failed too many times in a row, or straight away if the server says
+// it is gone for good.
+func markFeedFailed(ctx context.Context, s *State, feed database.Feed, fetchErr error) {
+	failures := int(feed.FailureCount) + 1
+	minInterval, maxInterval := s.cfg.FetchIntervalBounds()
+
+	disabled := failures >= s.cfg.FeedFailureLimit()
+	backoff := failureBackoff(failures, minInterval, maxInterval)
+
+	var statusErr *rss.StatusError
+	if errors.As(fetchErr, &statusErr) && statusErr.StatusCode == http.StatusGone {
+		disabled = true
+	}
+
+	err := s.db.MarkFeedFailed(ctx, database.MarkFeedFailedParams{
+		ID:          feed.ID,
+		LastError:   sql.NullString{String: fetchErr.Error(), Valid: true},
+		NextFetchAt: sql.NullTime{Time: time.Now().Add(backoff), Valid: true},
+		Disabled:    disabled,
+	})
+	if err != nil {
+		fmt.Printf("Error marking feed %s failed: %v\n", feed.Name, err)
+		return
+	}
+
+	if disabled {
+		fmt.Printf("Feed %s has been disabled, use \"feed enable %s\" to turn it back on\n", feed.Name, feed.Url)
+	}
+}
+
+// failureBackoff doubles the wait after every consecutive failure, starting
+// from the shortest fetch interval and never going past the longest.
+func failureBackoff(failures int, minInterval, maxInterval time.Duration) time.Duration {
+	backoff := minInterval
+	for i := 1; i < failures; i++ {
+		backoff *= 2
+		if backoff >= maxInterval {
+			return maxInterval
 		}
+	}
+
+	return backoff
+}
+
+// nextFetchInterval adapts how long to wait before fetching a feed again to
+// how often it publishes. A feed that had new posts is checked twice as often
+// next time and one that had nothing new gets half again as long, so busy
+// news feeds settle on short intervals and dormant blogs on long ones.
+func nextFetchInterval(current time.Duration, newPosts int, minInterval, maxInterval time.Duration) time.Duration {
+	next := current
+	if newPosts > 0 {
+		next = current / 2
+	} else {
+		next = current + current/2
+	}
+
+	if next < minInterval {
+		return minInterval
+	}
+	if next > maxInterval {
+		return maxInterval
+	}
+
+	return next
+}
+
+// postSaveResult says what savePost did with an item.
+type postSaveResult int
 
-		err = s.db.MarkFeedFetched(context.Background(), feed.ID)
+const (
+	postUnchanged postSaveResult = iota
+	postInserted
+	postUpdated
+)
+
+// storeFeedPosts saves new items and updates posts whose title or content
+// changed since they were stored. It returns how many posts were inserted
+// and how many were updated.
+func storeFeedPosts(ctx context.Context, s *State, feed database.Feed, articles *rss.Feed) (int, int, error) {
+	fetchedAt := time.Now()
+
+	inserted, updated := 0, 0
+	for _, article := range articles.Items {
+		saved, err := savePost(ctx, s, feed, article, fetchedAt)
 		if err != nil {
-			err := fmt.Errorf("error marking feed fetched: %v", err)
-			return err
+			err := fmt.Errorf("error saving post: %v", err)
+			return inserted, updated, err
 		}
 
-		for _, article := range articles.Channel.Item {
-			description := sql.NullString{
-				String: article.Description,
-				Valid:  article.Description != "",
-			}
+		switch saved {
+		case postInserted:
+			inserted++
+		case postUpdated:
+			updated++
+		}
+	}
 
-			pubDate, err := time.Parse(time.RFC1123Z, article.PubDate)
-			if err != nil {
-				fmt.Printf("Error parsing publication date: %v\n", err)
-				continue
-			}
+	return inserted, updated, nil
+}
 
-			_, err = s.db.CreatePost(context.Background(), database.CreatePostParams{
-				ID:          uuid.New(),
-				CreatedAt:   time.Now(),
-				UpdatedAt:   time.Now(),
-				FeedID:      feed.ID,
-				Title:       article.Title,
-				Url:         article.Link,
-				Description: description,
-				PublishedAt: pubDate,
-			})
-			if err != nil {
-				if pqErr, ok := err.(*pq.Error); ok {
-					if pqErr.Code == "23505" && pqErr.Constraint == "posts_url_key" {
-						continue
-					}
-				}
-				err := fmt.Errorf("error creating post: %v", err)
-				return err
-			}
+// postIngestVersion goes up whenever savePost changes what it stores for
+// the same item, like when descriptions started being sanitized. Posts from
+// an older version are brought up to date on their next fetch without it
+// counting as a revision, since the publisher didn't change anything.
+const postIngestVersion = 1
+
+// savePost upserts a single item together with its categories and
+// enclosures, in one transaction so a post is never left half written.
+func savePost(ctx context.Context, s *State, feed database.Feed, article rss.Item, fetchedAt time.Time) (postSaveResult, error) {
+	// Items with missing or unreadable dates are still worth keeping, the
+	// time we first saw them is the next best thing.
+	pubDate, ok := article.Published()
+	if !ok {
+		pubDate = fetchedAt
+	}
+
+	// Whatever ends up rendering posts gets HTML that can't run scripts,
+	// with relative links pointing back at the publisher.
+	base := postBaseURL(feed.Url, article.Base)
+	description := sanitize.HTML(article.Description, base)
+	content := sanitize.HTML(article.Content, base)
 
-			fmt.Printf("Article: %s\n", article.Title)
+	tx, err := s.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return postUnchanged, err
+	}
+	defer tx.Rollback()
+
+	qtx := s.db.WithTx(tx)
+
+	// Posts stored before items had keys of their own were keyed on their
+	// link. Move them over to the item's key, or they'd be stored again.
+	key := article.Key()
+	if legacyKey := article.LegacyKey(); legacyKey != "" && legacyKey != key {
+		err = qtx.AdoptLegacyPost(ctx, database.AdoptLegacyPostParams{
+			Guid:   key,
+			FeedID: feed.ID,
+			Url:    legacyKey,
+		})
+		if err != nil {
+			return postUnchanged, err
 		}
 	}
 
-	return nil
+	id := uuid.New()
+	post, err := qtx.UpsertPost(ctx, database.UpsertPostParams{
+		ID:            id,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+		FeedID:        feed.ID,
+		Title:         article.Title,
+		Url:           article.Link,
+		Description:   nullString(description),
+		PublishedAt:   pubDate,
+		Guid:          key,
+		Content:       nullString(content),
+		Author:        nullString(article.Author),
+		CommentsUrl:   nullString(article.Comments),
+		IngestVersion: postIngestVersion,
+	})
+	if err != nil {
+		// Nothing comes back when the stored post is already up to date,
+		// but a legacy post adopted above still has to keep its new key.
+		if errors.Is(err, sql.ErrNoRows) {
+			return postUnchanged, tx.Commit()
+		}
+		return postUnchanged, err
+	}
+
+	result := postInserted
+	if post.ID != id {
+		result = postUpdated
+
+		err = qtx.DeletePostCategories(ctx, post.ID)
+		if err != nil {
+			return postUnchanged, err
+		}
+
+		err = qtx.DeletePostEnclosures(ctx, post.ID)
+		if err != nil {
+			return postUnchanged, err
+		}
+	}
+
+	for _, category := range article.Categories {
+		err = qtx.CreatePostCategory(ctx, database.CreatePostCategoryParams{
+			PostID: post.ID,
+			Name:   category,
+		})
+		if err != nil {
+			return postUnchanged, err
+		}
+	}
+
+	for _, enclosure := range article.Enclosures {
+		err = qtx.CreatePostEnclosure(ctx, database.CreatePostEnclosureParams{
+			ID:     uuid.New(),
+			PostID: post.ID,
+			Url:    enclosure.URL,
+			Type:   nullString(enclosure.Type),
+			Length: sql.NullInt64{Int64: enclosure.Length, Valid: enclosure.Length > 0},
+		})
+		if err != nil {
+			return postUnchanged, err
+		}
+	}
+
+	if article.Podcast != nil {
+		episode := article.Podcast
+		err = qtx.UpsertPodcastEpisode(ctx, database.UpsertPodcastEpisodeParams{
+			PostID:          post.ID,
+			MediaUrl:        episode.Media.URL,
+			MediaType:       nullString(episode.Media.Type),
+			MediaLength:     sql.NullInt64{Int64: episode.Media.Length, Valid: episode.Media.Length > 0},
+			DurationSeconds: sql.NullInt32{Int32: int32(episode.Duration / time.Second), Valid: episode.Duration > 0},
+			Episode:         sql.NullInt32{Int32: int32(episode.Episode), Valid: episode.Episode > 0},
+			Season:          sql.NullInt32{Int32: int32(episode.Season), Valid: episode.Season > 0},
+			ImageUrl:        nullString(episode.Image),
+			Explicit:        episode.Explicit,
+		})
+		if err != nil {
+			return postUnchanged, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return postUnchanged, err
+	}
+
+	return result, nil
+}
+
+// postBaseURL is what relative URLs in a post are resolved against. The
+// parser has already worked out the item's base from xml:base and the post
+// link, this only falls back to the feed URL when it couldn't.
+func postBaseURL(feedURL, itemBase string) *url.URL {
+	base, err := url.Parse(feedURL)
+	if err != nil {
+		return nil
+	}
+
+	postURL, err := url.Parse(strings.TrimSpace(itemBase))
+	if err != nil {
+		return base
+	}
+
+	return base.ResolveReference(postURL)
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
 }
 
 func handlerLogin(s *State, cmd Command) error {
@@ -254,43 +743,126 @@ func handlerUsers(s *State, cmd Command) error {
 }
 
 func handlerAgg(s *State, cmd Command) error {
-	if len(cmd.Args) != 1 {
-		err := fmt.Errorf("usage: %s <single|continuous>", cmd.Name)
+	if len(cmd.Args) < 1 || len(cmd.Args) > 2 {
+		err := fmt.Errorf("usage: %s <single|continuous> [interval]", cmd.Name)
 		return err
 	}
 
-	if cmd.Args[0] == "single" {
-		err := scrapeFeeds(s)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch cmd.Args[0] {
+	case "single":
+		if len(cmd.Args) != 1 {
+			err := fmt.Errorf("usage: %s single", cmd.Name)
+			return err
+		}
+
+		err := scrapeFeeds(ctx, s)
 		if err != nil {
 			err := fmt.Errorf("error scraping feeds: %v", err)
 			return err
 		}
-	} else if cmd.Args[0] == "continuous" {
-		fmt.Printf("Continuous mode not implemented yet\n")
+	case "continuous":
+		interval := defaultAggInterval
+		if len(cmd.Args) == 2 {
+			var err error
+			interval, err = time.ParseDuration(cmd.Args[1])
+			if err != nil {
+				return fmt.Errorf("invalid interval value: %v", err)
+			}
+			if interval <= 0 {
+				return fmt.Errorf("invalid interval value: %s must be positive", cmd.Args[1])
+			}
+		}
+
+		return runContinuous(ctx, s, interval)
+	default:
+		err := fmt.Errorf("usage: %s <single|continuous> [interval]", cmd.Name)
+		return err
 	}
 
 	return nil
 }
 
+// runContinuous scrapes feeds every interval until ctx is cancelled. A
+// scrape that is in flight when a shutdown signal arrives sees the same
+// cancelled context, so it stops at its next query instead of being killed.
+func runContinuous(ctx context.Context, s *State, interval time.Duration) error {
+	fmt.Printf("Collecting feeds every %s, press Ctrl+C to stop\n", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := scrapeFeeds(ctx, s)
+		if err != nil && ctx.Err() == nil {
+			fmt.Printf("Error scraping feeds: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("Shutting down aggregator")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
 func handlerAddFeed(s *State, cmd Command, user database.User) error {
-	if len(cmd.Args) != 2 {
-		err := fmt.Errorf("usage: %s <name> <url>", cmd.Name)
+	if len(cmd.Args) < 1 || len(cmd.Args) > 2 {
+		err := fmt.Errorf("usage: %s <url> <name>(optional)", cmd.Name)
 		return err
 	}
 
+	feedURL, name := cmd.Args[0], ""
+	if len(cmd.Args) == 2 {
+		name = cmd.Args[1]
+		// The name used to come first, so keep accepting that order.
+		if !isWebURL(feedURL) && isWebURL(name) {
+			feedURL, name = name, feedURL
+		}
+	}
+
+	feedURL, parsed, err := fetchNewFeed(context.Background(), s, feedURL)
+	if err != nil {
+		// A feed that is down right now can still be added under a name of
+		// the user's choosing, agg will keep trying it.
+		if name == "" || unfetchableFeed(err) {
+			return err
+		}
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	if name == "" {
+		name = parsed.Title
+		if name == "" {
+			err := fmt.Errorf("feed has no title, give it a name with: %s <url> <name>", cmd.Name)
+			return err
+		}
+	}
+
 	feed, err := s.db.CreateFeed(context.Background(), database.CreateFeedParams{
 		ID:        uuid.New(),
 		UserID:    user.ID,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
-		Name:      cmd.Args[0],
-		Url:       cmd.Args[1],
+		Name:      name,
+		Url:       feedURL,
 	})
 	if err != nil {
 		err := fmt.Errorf("error creating feed: %v", err)
 		return err
 	}
 
+	if parsed != nil {
+		err = updateFeedMetadata(context.Background(), s, feed.ID, parsed)
+		if err != nil {
+			err := fmt.Errorf("error updating feed metadata: %v", err)
+			return err
+		}
+	}
+
 	_, err = s.db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
 		ID:        uuid.New(),
 		CreatedAt: time.Now(),
@@ -308,6 +880,91 @@ func handlerAddFeed(s *State, cmd Command, user database.User) error {
 	return nil
 }
 
+var errNoFeedChosen = errors.New("no feed chosen")
+
+// unfetchableFeed reports whether a failed fetch means the URL can never be
+// fetched as a feed, rather than that it is down for now: it is a web page,
+// the user didn't pick a feed from one, or the URL isn't allowed at all.
+func unfetchableFeed(err error) bool {
+	return errors.Is(err, rss.ErrHTMLPage) ||
+		errors.Is(err, errNoFeedChosen) ||
+		errors.Is(err, rss.ErrForbiddenAddress) ||
+		errors.Is(err, rss.ErrUnsupportedScheme)
+}
+
+// fetchNewFeed fetches a feed that is about to be added. When the URL is a
+// web page rather than a feed, the feeds it links to are looked up instead:
+// a single one is used as is, and the user picks when there are several.
+func fetchNewFeed(ctx context.Context, s *State, feedURL string) (string, *rss.Feed, error) {
+	fetched, err := s.fetcher.Fetch(ctx, feedURL, rss.Cache{})
+	if err == nil {
+		return feedURL, fetched.Feed, nil
+	}
+	if !errors.Is(err, rss.ErrHTMLPage) {
+		err := fmt.Errorf("error fetching feed: %w", err)
+		return feedURL, nil, err
+	}
+
+	found, err := s.fetcher.Discover(ctx, feedURL)
+	if err != nil {
+		err := fmt.Errorf("%w and looking for feeds on it failed: %v", rss.ErrHTMLPage, err)
+		return feedURL, nil, err
+	}
+
+	var choice rss.DiscoveredFeed
+	switch len(found) {
+	case 0:
+		err := fmt.Errorf("%w and no feed could be found on it", rss.ErrHTMLPage)
+		return feedURL, nil, err
+	case 1:
+		choice = found[0]
+		fmt.Printf("Found feed %s\n", choice.URL)
+	default:
+		choice, err = chooseFeed(found)
+		if err != nil {
+			return feedURL, nil, err
+		}
+	}
+
+	fetched, err = s.fetcher.Fetch(ctx, choice.URL, rss.Cache{})
+	if err != nil {
+		err := fmt.Errorf("error fetching feed: %v", err)
+		return choice.URL, nil, err
+	}
+
+	return choice.URL, fetched.Feed, nil
+}
+
+// chooseFeed lists the feeds found on a page and asks which one to add.
+func chooseFeed(found []rss.DiscoveredFeed) (rss.DiscoveredFeed, error) {
+	fmt.Println("Found these feeds:")
+	for i, feed := range found {
+		title := feed.Title
+		if title == "" {
+			title = feed.Type
+		}
+		fmt.Printf("%d. %s (%s)\n", i+1, feed.URL, title)
+	}
+
+	fmt.Printf("Which one should be added? [1-%d]: ", len(found))
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return rss.DiscoveredFeed{}, errNoFeedChosen
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(found) {
+		return rss.DiscoveredFeed{}, errNoFeedChosen
+	}
+
+	return found[n-1], nil
+}
+
+func isWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 func handlerFeeds(s *State, cmd Command) error {
 	if len(cmd.Args) != 0 {
 		err := fmt.Errorf("usage: %s", cmd.Name)
@@ -325,6 +982,83 @@ func handlerFeeds(s *State, cmd Command) error {
 	return nil
 }
 
+func handlerFeedHealth(s *State, cmd Command) error {
+	if len(cmd.Args) != 0 {
+		err := fmt.Errorf("usage: %s", cmd.Name)
+		return err
+	}
+
+	feeds, err := s.db.GetFailingFeeds(context.Background())
+	if err != nil {
+		err := fmt.Errorf("error getting failing feeds: %v", err)
+		return err
+	}
+
+	if len(feeds) == 0 {
+		fmt.Println("All feeds are healthy")
+		return nil
+	}
+
+	for _, feed := range feeds {
+		lastSuccess := "never"
+		if feed.LastSuccessAt.Valid {
+			lastSuccess = feed.LastSuccessAt.Time.Format(time.RFC1123)
+		}
+
+		status := ""
+		if feed.Disabled {
+			status = " [disabled]"
+		}
+
+		fmt.Printf("* %s (%s)%s\n", feed.Name, feed.Url, status)
+		if feed.FailureCount > 0 || feed.Disabled {
+			fmt.Printf("  Consecutive failures: %d\n", feed.FailureCount)
+			fmt.Printf("  Last error: %s\n", feed.LastError.String)
+		}
+		if feed.PostponeCount > 0 {
+			fmt.Printf("  Postponed %d times in a row, the host keeps asking us to come back later\n", feed.PostponeCount)
+		}
+		fmt.Printf("  Last success: %s\n", lastSuccess)
+		if feed.ParseMode.String == string(rss.ParseLenient) {
+			fmt.Printf("  Parse mode: lenient, the feed isn't well-formed XML\n")
+		}
+	}
+
+	return nil
+}
+
+func handlerFeed(s *State, cmd Command) error {
+	if len(cmd.Args) != 2 {
+		err := fmt.Errorf("usage: %s <enable|disable> <feed url>", cmd.Name)
+		return err
+	}
+
+	var rows int64
+	var err error
+	switch cmd.Args[0] {
+	case "enable":
+		rows, err = s.db.EnableFeed(context.Background(), cmd.Args[1])
+	case "disable":
+		rows, err = s.db.DisableFeed(context.Background(), cmd.Args[1])
+	default:
+		err := fmt.Errorf("usage: %s <enable|disable> <feed url>", cmd.Name)
+		return err
+	}
+	if err != nil {
+		err := fmt.Errorf("error updating feed: %v", err)
+		return err
+	}
+
+	if rows == 0 {
+		err := fmt.Errorf("feed not found: %s", cmd.Args[1])
+		return err
+	}
+
+	fmt.Printf("Feed %s has been %sd\n", cmd.Args[1], cmd.Args[0])
+
+	return nil
+}
+
 func handlerFollow(s *State, cmd Command, user database.User) error {
 	if len(cmd.Args) != 1 {
 		err := fmt.Errorf("usage: %s <feed name>", cmd.Name)
@@ -408,21 +1142,33 @@ func handlerUnfollow(s *State, cmd Command, user database.User) error {
 }
 
 func handlerBrowse(s *State, cmd Command, user database.User) error {
-	if len(cmd.Args) > 1 {
-		err := fmt.Errorf("usage: %s <limit#>(optional)", cmd.Name)
+	raw := false
+	var args []string
+	for _, arg := range cmd.Args {
+		if arg == "--raw" {
+			raw = true
+			continue
+		}
+		args = append(args, arg)
+	}
+
+	if len(args) > 1 {
+		err := fmt.Errorf("usage: %s <limit#>(optional) [--raw]", cmd.Name)
 		return err
 	}
 
 	limit := 2
 
-	if len(cmd.Args) == 1 {
+	if len(args) == 1 {
 		var err error
-		limit, err = strconv.Atoi(cmd.Args[0])
+		limit, err = strconv.Atoi(args[0])
 		if err != nil {
 			return fmt.Errorf("invalid limit value: %v", err)
 		}
 	}
 
+	width := terminalWidth()
+
 	posts, err := s.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
 		UserID: user.ID,
 		Limit:  int32(limit),
@@ -433,12 +1179,195 @@ func handlerBrowse(s *State, cmd Command, user database.User) error {
 	}
 
 	for _, post := range posts {
-		fmt.Printf("* Title: %s\n", post.Title)
+		if post.RevisionCount > 0 {
+			fmt.Printf("* Title: %s (updated %s)\n", post.Title, post.UpdatedAt.Format(time.RFC1123))
+		} else {
+			fmt.Printf("* Title: %s\n", post.Title)
+		}
 		fmt.Printf("* Url: %s\n", post.Url)
-		fmt.Printf("* Description: %s\n", post.Description.String)
+		if post.Author.Valid {
+			fmt.Printf("* Author: %s\n", post.Author.String)
+		}
+
+		categories, err := s.db.GetPostCategories(context.Background(), post.ID)
+		if err != nil {
+			err := fmt.Errorf("error getting post categories: %v", err)
+			return err
+		}
+		if len(categories) > 0 {
+			fmt.Printf("* Categories: %s\n", strings.Join(categories, ", "))
+		}
+
+		if raw {
+			fmt.Printf("* Description: %s\n", post.Description.String)
+		} else {
+			fmt.Printf("* Description:\n%s\n", htmltext.Render(post.Description.String, width))
+		}
+		if post.CommentsUrl.Valid {
+			fmt.Printf("* Comments: %s\n", post.CommentsUrl.String)
+		}
+
+		enclosures, err := s.db.GetPostEnclosures(context.Background(), post.ID)
+		if err != nil {
+			err := fmt.Errorf("error getting post enclosures: %v", err)
+			return err
+		}
+		for _, enclosure := range enclosures {
+			fmt.Printf("* Attachment: %s (%s)\n", enclosure.Url, enclosure.Type.String)
+		}
+
 		fmt.Printf("* Published At: %s\n", post.PublishedAt)
 		fmt.Printf("\n")
 	}
 
 	return nil
 }
+
+// terminalWidth is how wide text can be printed, taken from the terminal or
+// failing that the COLUMNS variable.
+func terminalWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err == nil && width > 0 {
+		return width
+	}
+
+	width, err = strconv.Atoi(os.Getenv("COLUMNS"))
+	if err == nil && width > 0 {
+		return width
+	}
+
+	return defaultTerminalWidth
+}
+
+func handlerPodcasts(s *State, cmd Command, user database.User) error {
+	args := cmd.Args
+	download := len(args) > 0 && args[0] == "download"
+	if download {
+		args = args[1:]
+	}
+
+	if len(args) > 1 {
+		err := fmt.Errorf("usage: %s [download] <limit#>(optional)", cmd.Name)
+		return err
+	}
+
+	limit := defaultPodcastLimit
+
+	if len(args) == 1 {
+		var err error
+		limit, err = strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid limit value: %v", err)
+		}
+	}
+
+	episodes, err := s.db.GetPodcastEpisodesForUser(context.Background(), database.GetPodcastEpisodesForUserParams{
+		UserID: user.ID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		err := fmt.Errorf("error getting podcast episodes: %v", err)
+		return err
+	}
+
+	if download {
+		return downloadEpisodes(s, episodes)
+	}
+
+	for _, episode := range episodes {
+		duration := "unknown"
+		if episode.DurationSeconds.Valid {
+			duration = (time.Duration(episode.DurationSeconds.Int32) * time.Second).String()
+		}
+
+		fmt.Printf("* Podcast: %s\n", episode.FeedName)
+		fmt.Printf("* Title: %s\n", episode.Title)
+		if episode.Season.Valid {
+			fmt.Printf("* Season: %d\n", episode.Season.Int32)
+		}
+		if episode.Episode.Valid {
+			fmt.Printf("* Episode: %d\n", episode.Episode.Int32)
+		}
+		if episode.Explicit {
+			fmt.Printf("* Explicit: yes\n")
+		}
+		fmt.Printf("* Duration: %s\n", duration)
+		fmt.Printf("* Media: %s\n", episode.MediaUrl)
+		fmt.Printf("* Published At: %s\n", episode.PublishedAt)
+		fmt.Printf("\n")
+	}
+
+	return nil
+}
+
+// downloadEpisodes saves episodes into a folder per podcast under the
+// configured podcast directory. Episodes that are already there are skipped
+// and interrupted downloads pick up where they left off.
+func downloadEpisodes(s *State, episodes []database.GetPodcastEpisodesForUserRow) error {
+	dir, err := s.cfg.PodcastDirectory()
+	if err != nil {
+		err := fmt.Errorf("error getting podcast directory: %v", err)
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, episode := range episodes {
+		podcastDir := filepath.Join(dir, safeFileName(episode.FeedName))
+		err := os.MkdirAll(podcastDir, 0755)
+		if err != nil {
+			err := fmt.Errorf("error creating podcast directory: %v", err)
+			return err
+		}
+
+		file := filepath.Join(podcastDir, episodeFileName(episode))
+		if _, err := os.Stat(file); err == nil {
+			fmt.Printf("* %s: already downloaded\n", episode.Title)
+			continue
+		}
+
+		fmt.Printf("* %s: downloading %s\n", episode.Title, episode.MediaUrl)
+		err = s.fetcher.Download(ctx, episode.MediaUrl, file)
+		if err != nil {
+			if ctx.Err() != nil {
+				fmt.Println("Download stopped, run the command again to resume it")
+				return nil
+			}
+			fmt.Printf("* %s: failed: %v\n", episode.Title, err)
+			continue
+		}
+
+		fmt.Printf("* %s: saved to %s\n", episode.Title, file)
+	}
+
+	return nil
+}
+
+// episodeFileName names the file after the episode title, since podcast
+// hosts often give every episode's media the same file name. The extension
+// comes from the media URL.
+func episodeFileName(episode database.GetPodcastEpisodesForUserRow) string {
+	name := safeFileName(episode.Title)
+	if name == "" {
+		name = episode.PostID.String()
+	}
+
+	mediaURL, err := url.Parse(episode.MediaUrl)
+	if err == nil {
+		name += path.Ext(mediaURL.Path)
+	}
+
+	return name
+}
+
+func safeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	return strings.Trim(strings.TrimSpace(name), ".")
+}
@@ -0,0 +1,10 @@
+-- name: AdoptLegacyPost :exec
+UPDATE posts
+    SET guid = $1
+WHERE feed_id = $2
+    AND guid = url
+    AND url = $3
+    AND NOT EXISTS (
+        SELECT 1 FROM posts AS keyed
+        WHERE keyed.feed_id = $2 AND keyed.guid = $1
+    );
\ No newline at end of file
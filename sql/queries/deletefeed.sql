@@ -0,0 +1,2 @@
+-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- name: DeleteFeedPosts :exec
+DELETE FROM posts WHERE feed_id = $1;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: DisableFeed :execrows
+UPDATE feeds
+    SET disabled = TRUE,
+        updated_at = NOW()
+WHERE url = $1;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- name: EnableFeed :execrows
+UPDATE feeds
+    SET disabled = FALSE,
+        failure_count = 0,
+        next_fetch_at = NULL,
+        updated_at = NOW()
+WHERE url = $1;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- name: GetFailingFeeds :many
+SELECT
+    *
+FROM feeds
+WHERE failure_count > 0 OR disabled OR parse_mode = 'lenient' OR postpone_count > 0
+ORDER BY disabled DESC, failure_count DESC, postpone_count DESC, name;
\ No newline at end of file
@@ -2,5 +2,6 @@
 SELECT
     *
 FROM feeds
-WHERE last_fetched_at IS NULL
-ORDER BY updated_at DESC;
\ No newline at end of file
+WHERE NOT disabled
+    AND (next_fetch_at IS NULL OR next_fetch_at <= NOW())
+ORDER BY next_fetch_at ASC NULLS FIRST;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- name: MarkFeedFailed :exec
+UPDATE feeds
+    SET last_fetched_at = NOW(),
+        updated_at = NOW(),
+        last_error = $2,
+        failure_count = failure_count + 1,
+        postpone_count = 0,
+        next_fetch_at = $3,
+        disabled = $4
+WHERE id = $1;
\ No newline at end of file
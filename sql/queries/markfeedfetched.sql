@@ -1,5 +1,13 @@
 -- name: MarkFeedFetched :exec
 UPDATE feeds
     SET last_fetched_at = NOW(),
-        updated_at = NOW()
+        updated_at = NOW(),
+        last_success_at = NOW(),
+        last_error = NULL,
+        failure_count = 0,
+        postpone_count = 0,
+        etag = $2,
+        last_modified = $3,
+        fetch_interval_seconds = $4,
+        next_fetch_at = $5
 WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- name: MoveFeedFollows :exec
+UPDATE feed_follows
+    SET feed_id = sqlc.arg(to_feed_id),
+        updated_at = NOW()
+WHERE feed_id = sqlc.arg(from_feed_id)
+    AND user_id NOT IN (
+        SELECT user_id FROM feed_follows WHERE feed_id = sqlc.arg(to_feed_id)
+    );
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- name: MoveFeedPosts :exec
+UPDATE posts
+    SET feed_id = sqlc.arg(to_feed_id),
+        updated_at = NOW()
+WHERE feed_id = sqlc.arg(from_feed_id)
+    AND guid NOT IN (
+        SELECT guid FROM posts WHERE feed_id = sqlc.arg(to_feed_id)
+    );
\ No newline at end of file
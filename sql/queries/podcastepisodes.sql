@@ -0,0 +1,36 @@
+-- name: UpsertPodcastEpisode :exec
+INSERT INTO podcast_episodes (post_id, media_url, media_type, media_length, duration_seconds, episode, season, image_url, explicit)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+ON CONFLICT (post_id) DO UPDATE SET
+    media_url = EXCLUDED.media_url,
+    media_type = EXCLUDED.media_type,
+    media_length = EXCLUDED.media_length,
+    duration_seconds = EXCLUDED.duration_seconds,
+    episode = EXCLUDED.episode,
+    season = EXCLUDED.season,
+    image_url = EXCLUDED.image_url,
+    explicit = EXCLUDED.explicit;
+
+-- name: GetPodcastEpisodesForUser :many
+SELECT
+    pe.*,
+    p.title,
+    p.published_at,
+    f.name AS feed_name
+FROM podcast_episodes pe
+JOIN posts p ON pe.post_id = p.id
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON ff.feed_id = f.id
+WHERE ff.user_id = $1
+ORDER BY p.published_at DESC
+LIMIT $2;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- name: CreatePostCategory :exec
+INSERT INTO post_categories (post_id, name)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeletePostCategories :exec
+DELETE FROM post_categories WHERE post_id = $1;
+
+-- name: GetPostCategories :many
+SELECT name FROM post_categories WHERE post_id = $1 ORDER BY name;
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- name: CreatePostEnclosure :exec
+INSERT INTO post_enclosures (id, post_id, url, type, length)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (post_id, url) DO NOTHING;
+
+-- name: DeletePostEnclosures :exec
+DELETE FROM post_enclosures WHERE post_id = $1;
+
+-- name: GetPostEnclosures :many
+SELECT * FROM post_enclosures WHERE post_id = $1;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- name: RescheduleFeed :exec
+UPDATE feeds
+    SET next_fetch_at = $2,
+        postpone_count = postpone_count + 1,
+        updated_at = NOW()
+WHERE id = $1;
\ No newline at end of file
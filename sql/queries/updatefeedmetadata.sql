@@ -0,0 +1,10 @@
+-- name: UpdateFeedMetadata :exec
+UPDATE feeds
+    SET title = $2,
+        site_url = $3,
+        description = $4,
+        image_url = $5,
+        language = $6,
+        parse_mode = $7,
+        updated_at = NOW()
+WHERE id = $1;
\ No newline at end of file
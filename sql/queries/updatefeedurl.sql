@@ -0,0 +1,5 @@
+-- name: UpdateFeedUrl :exec
+UPDATE feeds
+    SET url = $2,
+        updated_at = NOW()
+WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,48 @@
+-- name: UpsertPost :one
+INSERT INTO posts (
+    id,
+    created_at, 
+    updated_at, 
+    title, 
+    url, 
+    description,
+    published_at,
+    feed_id,
+    guid,
+    content,
+    author,
+    comments_url,
+    ingest_version
+    )
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
+)
+ON CONFLICT (feed_id, guid) DO UPDATE
+    SET title = EXCLUDED.title,
+        url = EXCLUDED.url,
+        description = EXCLUDED.description,
+        content = EXCLUDED.content,
+        author = EXCLUDED.author,
+        comments_url = EXCLUDED.comments_url,
+        ingest_version = EXCLUDED.ingest_version,
+        updated_at = CASE WHEN posts.ingest_version = EXCLUDED.ingest_version
+            THEN EXCLUDED.updated_at ELSE posts.updated_at END,
+        revision_count = CASE WHEN posts.ingest_version = EXCLUDED.ingest_version
+            THEN posts.revision_count + 1 ELSE posts.revision_count END
+    WHERE posts.title IS DISTINCT FROM EXCLUDED.title
+        OR posts.description IS DISTINCT FROM EXCLUDED.description
+        OR posts.content IS DISTINCT FROM EXCLUDED.content
+        OR posts.ingest_version <> EXCLUDED.ingest_version
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN last_error TEXT;
+ALTER TABLE feeds ADD COLUMN failure_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feeds ADD COLUMN last_success_at TIMESTAMP WITH TIME ZONE;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN last_success_at;
+ALTER TABLE feeds DROP COLUMN failure_count;
+ALTER TABLE feeds DROP COLUMN last_error;
\ No newline at end of file
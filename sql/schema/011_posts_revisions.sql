@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN revision_count INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN revision_count;
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE podcast_episodes (
+    post_id UUID PRIMARY KEY,
+    media_url TEXT NOT NULL,
+    media_type TEXT,
+    media_length BIGINT,
+    duration_seconds INTEGER,
+    episode INTEGER,
+    season INTEGER,
+    image_url TEXT,
+    explicit BOOLEAN NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE podcast_episodes;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN parse_mode TEXT;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN parse_mode;
\ No newline at end of file
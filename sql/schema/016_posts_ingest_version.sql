@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN ingest_version INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN ingest_version;
\ No newline at end of file
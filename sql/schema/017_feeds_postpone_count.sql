@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN postpone_count INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN postpone_count;
\ No newline at end of file